@@ -0,0 +1,178 @@
+package gocache
+
+import "container/list"
+
+// arcList identifies the list a key of an [arcPolicy] is in.
+type arcList int
+
+const (
+	// t1 holds the resident keys seen once recently.
+	t1 arcList = iota
+	// t2 holds the resident keys seen at least twice recently.
+	t2
+	// b1 holds the ghosts of the keys evicted from t1.
+	b1
+	// b2 holds the ghosts of the keys evicted from t2.
+	b2
+)
+
+// arcNode is a key of an [arcPolicy] along with the list it is in.
+type arcNode struct {
+	key  any
+	list arcList
+}
+
+// arcPolicy is an [EvictionPolicy] implementing the Adaptive Replacement Cache.
+// Resident keys are split between t1, for keys seen once, and t2, for keys seen at least twice, both
+// ordered by recency. Evicted keys are remembered in the ghost lists b1 and b2, without their values.
+// A new key found in b1 means t1 was too small, so its target size p grows, and a new key found in b2
+// means t2 was too small, so p shrinks. Evictions take from t1 while it is larger than p, and from t2
+// otherwise, which balances recency and frequency without tuning.
+type arcPolicy struct {
+	lists    [4]*list.List
+	elements map[any]*list.Element
+
+	// capacity is the number of resident keys, the ghost lists hold as many keys as well.
+	capacity int
+	// p is the target size of t1.
+	p int
+}
+
+// ARC creates an [EvictionPolicy] implementing the Adaptive Replacement Cache, which adapts between
+// evicting the least recently and the least frequently used entries based on the keys it recently evicted.
+func ARC() EvictionPolicy {
+	p := &arcPolicy{elements: make(map[any]*list.Element)}
+
+	for i := range p.lists {
+		p.lists[i] = list.New()
+	}
+
+	p.setCapacity(0)
+
+	return p
+}
+
+// setCapacity sets the number of resident keys the ghost lists are sized for.
+// A capacity less than 1 means unlimited, in which case the policy is sized for a small cache.
+func (p *arcPolicy) setCapacity(capacity int) {
+	if capacity < 1 {
+		capacity = 100
+	}
+
+	p.capacity = capacity
+}
+
+// OnInsert adds the key to t1, or to t2 if it was recently evicted, adapting the target size of t1.
+func (p *arcPolicy) OnInsert(key any) {
+	elem, ok := p.elements[key]
+
+	if !ok {
+		p.push(key, t1)
+		p.trimGhosts()
+
+		return
+	}
+
+	switch elem.Value.(*arcNode).list {
+	case b1:
+		p.p += max1(p.lists[b2].Len() / p.lists[b1].Len())
+		if p.p > p.capacity {
+			p.p = p.capacity
+		}
+	case b2:
+		p.p -= max1(p.lists[b1].Len() / p.lists[b2].Len())
+		if p.p < 0 {
+			p.p = 0
+		}
+	}
+
+	p.move(elem, t2)
+}
+
+// OnAccess moves the key to the front of t2.
+func (p *arcPolicy) OnAccess(key any) {
+	if elem, ok := p.elements[key]; ok {
+		if l := elem.Value.(*arcNode).list; l == t1 || l == t2 {
+			p.move(elem, t2)
+		}
+	}
+}
+
+// OnUpdate moves the key to the front of t2.
+func (p *arcPolicy) OnUpdate(key any) {
+	p.OnAccess(key)
+}
+
+// OnRemove stops tracking the key, without remembering it as a ghost.
+func (p *arcPolicy) OnRemove(key any) {
+	if elem, ok := p.elements[key]; ok {
+		p.lists[elem.Value.(*arcNode).list].Remove(elem)
+		delete(p.elements, key)
+	}
+}
+
+// Victim returns the least recently used key of t1 if it is larger than its target size, or of t2
+// otherwise, and remembers it in the matching ghost list.
+func (p *arcPolicy) Victim() (any, bool) {
+	from, ghost := t2, b2
+
+	if p.lists[t1].Len() > 0 && (p.lists[t1].Len() > p.p || p.lists[t2].Len() == 0) {
+		from, ghost = t1, b1
+	}
+
+	elem := p.lists[from].Back()
+
+	if elem == nil {
+		return nil, false
+	}
+
+	p.move(elem, ghost)
+	p.trimGhosts()
+
+	return elem.Value.(*arcNode).key, true
+}
+
+// trimGhosts drops the oldest ghosts so that t1 and b1 hold at most capacity keys, and all the lists
+// hold at most twice the capacity.
+func (p *arcPolicy) trimGhosts() {
+	for p.lists[t1].Len()+p.lists[b1].Len() > p.capacity && p.lists[b1].Len() > 0 {
+		p.drop(b1)
+	}
+
+	total := p.lists[t1].Len() + p.lists[t2].Len() + p.lists[b1].Len() + p.lists[b2].Len()
+
+	for ; total > 2*p.capacity && p.lists[b2].Len() > 0; total-- {
+		p.drop(b2)
+	}
+}
+
+// push adds the key to the front of the provided list.
+func (p *arcPolicy) push(key any, to arcList) {
+	p.elements[key] = p.lists[to].PushFront(&arcNode{key: key, list: to})
+}
+
+// move moves the element to the front of the provided list.
+func (p *arcPolicy) move(elem *list.Element, to arcList) {
+	node := elem.Value.(*arcNode)
+
+	p.lists[node.list].Remove(elem)
+	node.list = to
+	p.elements[node.key] = p.lists[to].PushFront(node)
+}
+
+// drop forgets the least recently used key of the provided list.
+func (p *arcPolicy) drop(from arcList) {
+	elem := p.lists[from].Back()
+
+	p.lists[from].Remove(elem)
+	delete(p.elements, elem.Value.(*arcNode).key)
+}
+
+// max1 returns n if it is at least 1, otherwise 1.
+func max1(n int) int {
+	if n < 1 {
+		return 1
+	}
+
+	return n
+}
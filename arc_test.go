@@ -0,0 +1,111 @@
+package gocache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestARCPolicyAdaptation(t *testing.T) {
+	// Setup
+	p := ARC().(*arcPolicy)
+	p.setCapacity(2)
+
+	// k1 is used twice and goes to t2, k2 is used once and stays in t1
+	p.OnInsert("k1")
+	p.OnAccess("k1")
+	p.OnInsert("k2")
+
+	// Test Case 1: Evicted keys become ghosts
+	t.Run("ghost on eviction", func(t *testing.T) {
+		if key, ok := p.Victim(); !ok || key != "k2" {
+			t.Errorf("Victim - got: %v, %v, want: k2, true", key, ok)
+		}
+
+		if node := p.elements["k2"].Value.(*arcNode); node.list != b1 {
+			t.Errorf("k2 list - got: %v, want: b1", node.list)
+		}
+
+		p.OnInsert("k3")
+	})
+
+	// Test Case 2: Hit in b1 grows the target size of t1
+	t.Run("ghost hit in b1", func(t *testing.T) {
+		if key, ok := p.Victim(); !ok || key != "k3" {
+			t.Errorf("Victim - got: %v, %v, want: k3, true", key, ok)
+		}
+
+		if p.p != 0 {
+			t.Errorf("p - got: %d, want: 0", p.p)
+		}
+
+		p.OnInsert("k2")
+
+		if p.p != 1 {
+			t.Errorf("p - got: %d, want: 1", p.p)
+		}
+
+		if node := p.elements["k2"].Value.(*arcNode); node.list != t2 {
+			t.Errorf("k2 list - got: %v, want: t2", node.list)
+		}
+	})
+
+	// Test Case 3: Hit in b2 shrinks the target size of t1
+	t.Run("ghost hit in b2", func(t *testing.T) {
+		// t1 is empty, so the least recently used key of t2 is evicted
+		if key, ok := p.Victim(); !ok || key != "k1" {
+			t.Errorf("Victim - got: %v, %v, want: k1, true", key, ok)
+		}
+
+		if node := p.elements["k1"].Value.(*arcNode); node.list != b2 {
+			t.Errorf("k1 list - got: %v, want: b2", node.list)
+		}
+
+		p.OnInsert("k1")
+
+		if p.p != 0 {
+			t.Errorf("p - got: %d, want: 0", p.p)
+		}
+	})
+
+	// Test Case 4: Ghost lists are bounded
+	t.Run("bounded ghosts", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			p.Victim()
+			p.OnInsert(fmt.Sprintf("n%d", i))
+		}
+
+		total := 0
+		for _, l := range p.lists {
+			total += l.Len()
+		}
+
+		if total > 2*p.capacity || len(p.elements) != total {
+			t.Errorf("tracked keys - got: %d (%d elements), want: at most %d", total, len(p.elements), 2*p.capacity)
+		}
+	})
+}
+
+func TestCacheARCEviction(t *testing.T) {
+	c := New(WithMaxKeys(4), WithEvictionPolicy(ARC))
+
+	// hot keys are used frequently
+	for i := 0; i < 3; i++ {
+		c.Set("hot1", i)
+		c.Get("hot1")
+		c.Set("hot2", i)
+		c.Get("hot2")
+	}
+
+	// a scan of keys used once goes through t1
+	for i := 0; i < 20; i++ {
+		c.Set(fmt.Sprintf("cold%d", i), i)
+	}
+
+	if !c.Has("hot1") || !c.Has("hot2") {
+		t.Error("Has hot1, hot2 - got: false, want: true")
+	}
+
+	if keys := c.Keys(); len(keys) != 4 {
+		t.Errorf("keys length - got: %d, want: 4", len(keys))
+	}
+}
@@ -0,0 +1,380 @@
+package gocache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BatchError is the error returned by GetManyOrLoad when some of the keys could not be loaded.
+// It holds the error of each of these keys, which is [ErrKeyNotFound] for the keys missing from the
+// result of the batch loader.
+type BatchError[K comparable] struct {
+	Errors map[K]error
+}
+
+// Error returns the errors of the keys that could not be loaded, ordered by key.
+func (e *BatchError[K]) Error() string {
+	errs := make([]string, 0, len(e.Errors))
+
+	for key, err := range e.Errors {
+		errs = append(errs, fmt.Sprintf("%v: %v", key, err))
+	}
+
+	sort.Strings(errs)
+
+	return fmt.Sprintf("gocache: failed to load %d keys: %s", len(e.Errors), strings.Join(errs, "; "))
+}
+
+// batcher groups the keys to load with a batch loader, across the concurrent calls to GetManyOrLoad,
+// until the batch window elapses or the batch is full.
+// It is shared between the shards of a [ShardedCache], so that the keys of all the shards are loaded together.
+type batcher[K comparable, V any] struct {
+	config
+
+	// mu guards the pending batch.
+	mu      sync.Mutex
+	pending *batch[K, V]
+}
+
+// batch is a group of keys to load with a single call to a batch loader.
+type batch[K comparable, V any] struct {
+	// ctx is the context of the batch loader, it carries the values of the context of the caller that
+	// started the batch, and is cancelled once no caller is waiting for any of its keys.
+	ctx    context.Context
+	cancel context.CancelFunc
+	loader func(ctx context.Context, missing []K) (map[K]V, error)
+	// timer dispatches the batch once the batch window elapses.
+	timer   Timer
+	entries []batchEntry[K, V]
+	// abandoned is the number of entries no caller is waiting for anymore.
+	abandoned int
+}
+
+// batchEntry is a key to load in a batch, along with the cache it belongs to and its load in progress.
+type batchEntry[K comparable, V any] struct {
+	cache  *TypedCache[K, V]
+	key    K
+	flight *flight[V]
+}
+
+// newBatcher creates a new batcher with the batch window and maximum batch size of the provided configurations.
+func newBatcher[K comparable, V any](cfg config) *batcher[K, V] {
+	return &batcher[K, V]{config: cfg}
+}
+
+// GetManyOrLoad returns the values associated with the provided keys from the cache, loading the keys
+// not found with a single call to the provided batch loader. The loaded values are set in the cache with
+// the global TTL of the cache.
+//
+// The keys missing from concurrent calls are loaded together in batches, within the batch window and up
+// to the maximum batch size, see [WithBatchWindow] and [WithMaxBatchSize]; each batch is loaded with the
+// batch loader of the call that started it. Keys already being loaded, including by GetOrLoad, are not
+// loaded again. The context of the batch loader carries the values of the context of the call that started
+// the batch.
+//
+// It returns the values that were found or loaded. If some keys could not be loaded, a [*BatchError]
+// holding the error of each of them is returned along with the other values. If the context is done
+// before all the keys are loaded, the values found so far are returned along with the context's error.
+//
+// The batch loader of a cache that is not safe for concurrent use, created with [New] or [NewTyped], is
+// called in the goroutine of the caller with its context instead, so that the cache is never modified from
+// another goroutine. The keys missing from the call are then loaded right away, in batches of up to the
+// maximum batch size.
+func (c *TypedCache[K, V]) GetManyOrLoad(ctx context.Context, keys []K, batchLoader func(ctx context.Context, missing []K) (map[K]V, error)) (map[K]V, error) {
+	obs := c.observe(ctx, OpGetManyOrLoad, "")
+
+	var values map[K]V
+	var err error
+
+	if c.mu == nil {
+		values, err = c.loadMany(obs.ctx, keys, batchLoader)
+	} else {
+		values, err = getManyOrLoad(obs.ctx, keys, c.batcher, func(K) *TypedCache[K, V] { return c }, batchLoader)
+	}
+
+	obs.done(errOutcome(err))
+
+	return values, err
+}
+
+// loadMany is GetManyOrLoad for a non-concurrent cache, whose keys not found are loaded in the goroutine of
+// the caller.
+func (c *TypedCache[K, V]) loadMany(ctx context.Context, keys []K, batchLoader func(ctx context.Context, missing []K) (map[K]V, error)) (map[K]V, error) {
+	values := make(map[K]V, len(keys))
+	errs := make(map[K]error)
+	seen := make(map[K]struct{}, len(keys))
+
+	var missing []K
+
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+
+		if value, err := c.read(key); err == nil {
+			values[key] = value
+		} else if errors.Is(err, ErrNegativeCached) {
+			errs[key] = err
+		} else {
+			missing = append(missing, key)
+		}
+	}
+
+	for len(missing) > 0 {
+		if err := ctx.Err(); err != nil {
+			return values, err
+		}
+
+		n := len(missing)
+		if c.maxBatchSize > 0 && n > c.maxBatchSize {
+			n = c.maxBatchSize
+		}
+
+		loaded, delta, err := callBatchLoader(ctx, c, missing[:n], batchLoader)
+
+		for _, key := range missing[:n] {
+			if value, keyErr := c.setBatchLoaded(key, loaded, err, delta); keyErr != nil {
+				errs[key] = keyErr
+			} else {
+				values[key] = value
+			}
+		}
+
+		missing = missing[n:]
+	}
+
+	if err := ctx.Err(); err != nil {
+		return values, err
+	}
+
+	if len(errs) > 0 {
+		return values, &BatchError[K]{Errors: errs}
+	}
+
+	return values, nil
+}
+
+// getManyOrLoad returns the values associated with the provided keys from the caches they belong to,
+// loading the keys not found in batches with the provided batcher, see [TypedCache.GetManyOrLoad].
+func getManyOrLoad[K comparable, V any](
+	ctx context.Context,
+	keys []K,
+	b *batcher[K, V],
+	cacheOf func(key K) *TypedCache[K, V],
+	batchLoader func(ctx context.Context, missing []K) (map[K]V, error),
+) (map[K]V, error) {
+	values := make(map[K]V, len(keys))
+	errs := make(map[K]error)
+	seen := make(map[K]struct{}, len(keys))
+
+	var waits []batchEntry[K, V]
+
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+
+		if value, err := cacheOf(key).read(key); err == nil {
+			values[key] = value
+		} else if errors.Is(err, ErrNegativeCached) {
+			errs[key] = err
+		} else if value, e, loading := b.join(ctx, cacheOf(key), key, batchLoader); loading {
+			waits = append(waits, e)
+		} else {
+			values[key] = value
+		}
+	}
+
+	if b.batchWindow == 0 {
+		b.dispatchPending()
+	}
+
+	for i, e := range waits {
+		select {
+		case <-e.flight.done:
+			if e.flight.err != nil {
+				errs[e.key] = e.flight.err
+			} else {
+				values[e.key] = e.flight.value
+			}
+		case <-ctx.Done():
+			for _, e := range waits[i:] {
+				e.cache.leaveFlight(e.key, e.flight)
+			}
+
+			return values, ctx.Err()
+		}
+	}
+
+	if len(errs) > 0 {
+		return values, &BatchError[K]{Errors: errs}
+	}
+
+	return values, nil
+}
+
+// join waits for the load of the provided key, adding it to the pending batch if it is not being loaded.
+// It returns the entry to wait for, or the value of the key and false if it was loaded in the meantime.
+func (b *batcher[K, V]) join(ctx context.Context, c *TypedCache[K, V], key K, batchLoader func(ctx context.Context, missing []K) (map[K]V, error)) (V, batchEntry[K, V], bool) {
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	var zero V
+
+	if f, ok := c.flights[key]; ok {
+		f.waiters++
+
+		return zero, batchEntry[K, V]{cache: c, key: key, flight: f}, true
+	}
+
+	// the key may have been loaded since the first lookup
+	if value, err := c.get(key); err == nil {
+		return value, batchEntry[K, V]{}, false
+	}
+
+	e := batchEntry[K, V]{cache: c, key: key, flight: &flight[V]{done: make(chan struct{}), waiters: 1}}
+
+	if c.flights == nil {
+		c.flights = make(map[K]*flight[V])
+	}
+
+	c.flights[key] = e.flight
+	b.add(ctx, e, batchLoader)
+
+	return zero, e, true
+}
+
+// add adds the entry to the pending batch, starting a new batch if there is none, and dispatches the
+// batch once full.
+// It requires the load lock of the cache of the entry.
+func (b *batcher[K, V]) add(ctx context.Context, e batchEntry[K, V], batchLoader func(ctx context.Context, missing []K) (map[K]V, error)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bt := b.pending
+	if bt == nil {
+		bt = &batch[K, V]{loader: batchLoader}
+		bt.ctx, bt.cancel = context.WithCancel(detachedContext{parent: ctx})
+		b.pending = bt
+
+		if b.batchWindow > 0 {
+			bt.timer = b.clock.AfterFunc(b.batchWindow, func() {
+				b.mu.Lock()
+				if b.pending == bt {
+					b.pending = nil
+				}
+				b.mu.Unlock()
+
+				b.dispatch(bt)
+			})
+		}
+	}
+
+	e.flight.cancel = func() {
+		b.abandon(bt)
+	}
+	bt.entries = append(bt.entries, e)
+
+	if b.maxBatchSize > 0 && len(bt.entries) >= b.maxBatchSize {
+		b.pending = nil
+
+		if bt.timer == nil || bt.timer.Stop() {
+			go b.dispatch(bt)
+		}
+	}
+}
+
+// dispatchPending dispatches the pending batch, if any.
+func (b *batcher[K, V]) dispatchPending() {
+	b.mu.Lock()
+	bt := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	if bt != nil {
+		go b.dispatch(bt)
+	}
+}
+
+// abandon records that no caller is waiting for one of the entries of the batch anymore, cancelling the
+// context of the batch loader once it is the case for all of them.
+func (b *batcher[K, V]) abandon(bt *batch[K, V]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bt.abandoned++
+
+	if bt.abandoned >= len(bt.entries) {
+		// the keys missing from later calls start a new batch rather than joining a cancelled one
+		if b.pending == bt {
+			b.pending = nil
+		}
+
+		bt.cancel()
+	}
+}
+
+// dispatch loads the keys of the batch with its batch loader, sets the loaded values in their caches,
+// then wakes up the callers waiting for them.
+func (b *batcher[K, V]) dispatch(bt *batch[K, V]) {
+	keys := make([]K, len(bt.entries))
+	for i, e := range bt.entries {
+		keys[i] = e.key
+	}
+
+	// the call is counted once, in the statistics of the cache of its first key
+	values, delta, err := callBatchLoader(bt.ctx, bt.entries[0].cache, keys, bt.loader)
+	bt.cancel()
+
+	for _, e := range bt.entries {
+		// the value is set before the flight ends, so that later callers find it in the cache
+		value, keyErr := e.cache.setBatchLoaded(e.key, values, err, delta)
+		e.cache.endFlight(e.key, e.flight, value, keyErr)
+	}
+}
+
+// callBatchLoader calls the batch loader with the provided keys, counting the call in the statistics of the
+// provided cache. It returns the loaded values along with the time it took to load them.
+func callBatchLoader[K comparable, V any](
+	ctx context.Context,
+	c *TypedCache[K, V],
+	keys []K,
+	batchLoader func(ctx context.Context, missing []K) (map[K]V, error),
+) (map[K]V, time.Duration, error) {
+	obs := c.observe(ctx, OpBatchLoad, "")
+	start := c.clock.Now()
+	values, err := batchLoader(obs.ctx, keys)
+	delta := c.clock.Now().Sub(start)
+	c.stats.loaded(delta, err)
+	obs.done(errOutcome(err))
+
+	return values, delta, err
+}
+
+// setBatchLoaded sets the value of the provided key loaded by a batch loader in the cache, or caches the key
+// as not found if it is missing from the loaded values.
+// It returns the value along with the error of the key, which is [ErrKeyNotFound] if it is missing.
+func (c *TypedCache[K, V]) setBatchLoaded(key K, values map[K]V, err error, delta time.Duration) (V, error) {
+	value, ok := values[key]
+
+	if err == nil && !ok {
+		err = ErrKeyNotFound
+	}
+
+	if err == nil {
+		c.setLoaded(key, value, -1, delta)
+	} else {
+		c.cacheMiss(key, err)
+	}
+
+	return value, err
+}
@@ -0,0 +1,326 @@
+package gocache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchRecorder is a batch loader recording the keys of each of its calls, loading the value "v-<key>"
+// for each key except those listed as missing.
+type batchRecorder struct {
+	mu      sync.Mutex
+	calls   [][]string
+	missing map[string]bool
+	err     error
+	release chan struct{}
+}
+
+func (r *batchRecorder) load(ctx context.Context, keys []string) (map[string]any, error) {
+	if r.release != nil {
+		select {
+		case <-r.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+
+	r.mu.Lock()
+	r.calls = append(r.calls, sorted)
+	r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	values := make(map[string]any, len(keys))
+
+	for _, key := range keys {
+		if !r.missing[key] {
+			values[key] = "v-" + key
+		}
+	}
+
+	return values, nil
+}
+
+func (r *batchRecorder) callCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.calls)
+}
+
+func TestCacheGetManyOrLoad(t *testing.T) {
+	// Setup
+	c := NewSync(WithStdTtl(time.Minute))
+	ctx := context.Background()
+	c.Set("k1", "value1")
+
+	// Test Case 1: Hits are returned and misses are loaded in a single call
+	t.Run("load misses", func(t *testing.T) {
+		rec := &batchRecorder{}
+		values, err := c.GetManyOrLoad(ctx, []string{"k1", "k2", "k3", "k2"}, rec.load)
+
+		if err != nil {
+			t.Errorf("GetManyOrLoad: err - got: %v, want: nil", err)
+		}
+
+		if len(values) != 3 || values["k1"] != "value1" || values["k2"] != "v-k2" || values["k3"] != "v-k3" {
+			t.Errorf("values - got: %v, want: map[k1:value1 k2:v-k2 k3:v-k3]", values)
+		}
+
+		if len(rec.calls) != 1 || fmt.Sprint(rec.calls[0]) != "[k2 k3]" {
+			t.Errorf("calls - got: %v, want: [[k2 k3]]", rec.calls)
+		}
+
+		if ttl := c.GetTtl("k2"); ttl != time.Minute {
+			t.Errorf("GetTtl k2 - got: %v, want: 1m", ttl)
+		}
+	})
+
+	// Test Case 2: Keys missing from the result are reported
+	t.Run("missing keys", func(t *testing.T) {
+		rec := &batchRecorder{missing: map[string]bool{"k5": true}}
+		values, err := c.GetManyOrLoad(ctx, []string{"k4", "k5"}, rec.load)
+
+		var batchErr *BatchError[string]
+		if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 || !errors.Is(batchErr.Errors["k5"], ErrKeyNotFound) {
+			t.Errorf("GetManyOrLoad: err - got: %v, want: k5: ErrKeyNotFound", err)
+		}
+
+		if len(values) != 1 || values["k4"] != "v-k4" {
+			t.Errorf("values - got: %v, want: map[k4:v-k4]", values)
+		}
+
+		if c.Has("k5") {
+			t.Errorf("has key k5 - got: true, want: false")
+		}
+	})
+
+	// Test Case 3: Loader errors are reported for every missing key
+	t.Run("loader error", func(t *testing.T) {
+		errLoad := errors.New("load failed")
+		rec := &batchRecorder{err: errLoad}
+		values, err := c.GetManyOrLoad(ctx, []string{"k1", "k6", "k7"}, rec.load)
+
+		var batchErr *BatchError[string]
+		if !errors.As(err, &batchErr) || len(batchErr.Errors) != 2 || !errors.Is(batchErr.Errors["k6"], errLoad) {
+			t.Errorf("GetManyOrLoad: err - got: %v, want: k6, k7: %v", err, errLoad)
+		}
+
+		if want := "gocache: failed to load 2 keys: k6: load failed; k7: load failed"; err.Error() != want {
+			t.Errorf("error - got: %s, want: %s", err.Error(), want)
+		}
+
+		if len(values) != 1 {
+			t.Errorf("values - got: %v, want: map[k1:value1]", values)
+		}
+	})
+
+	// Test Case 4: Context cancellation
+	t.Run("cancel", func(t *testing.T) {
+		rec := &batchRecorder{release: make(chan struct{})}
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		values, err := c.GetManyOrLoad(ctx, []string{"k1", "k8"}, rec.load)
+
+		if !errors.Is(err, context.DeadlineExceeded) || len(values) != 1 {
+			t.Errorf("GetManyOrLoad - got: %v, %v, want: map[k1:value1], context.DeadlineExceeded", values, err)
+		}
+
+		waitForLoads(t, c.Cache)
+
+		if rec.callCount() != 0 {
+			t.Errorf("calls - got: %v, want: none", rec.calls)
+		}
+	})
+}
+
+func TestCacheGetManyOrLoadBatching(t *testing.T) {
+	// Test Case 1: Concurrent calls within the window are loaded together
+	t.Run("window", func(t *testing.T) {
+		c := NewSync(WithBatchWindow(50 * time.Millisecond))
+		rec := &batchRecorder{}
+
+		var wg sync.WaitGroup
+
+		for _, keys := range [][]string{{"k1", "k2"}, {"k2", "k3"}, {"k4"}} {
+			wg.Add(1)
+
+			go func(keys []string) {
+				defer wg.Done()
+
+				if values, err := c.GetManyOrLoad(context.Background(), keys, rec.load); err != nil || len(values) != len(keys) {
+					t.Errorf("GetManyOrLoad %v - got: %v, %v, want: %d values, nil", keys, values, err, len(keys))
+				}
+			}(keys)
+		}
+
+		wg.Wait()
+
+		if len(rec.calls) != 1 || fmt.Sprint(rec.calls[0]) != "[k1 k2 k3 k4]" {
+			t.Errorf("calls - got: %v, want: [[k1 k2 k3 k4]]", rec.calls)
+		}
+	})
+
+	// Test Case 2: Full batches are loaded without waiting for the window
+	t.Run("max batch size", func(t *testing.T) {
+		c := NewSync(WithBatchWindow(time.Hour), WithMaxBatchSize(2))
+		rec := &batchRecorder{}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		values, err := c.GetManyOrLoad(ctx, []string{"k1", "k2", "k3", "k4"}, rec.load)
+
+		if err != nil || len(values) != 4 {
+			t.Errorf("GetManyOrLoad - got: %v, %v, want: 4 values, nil", values, err)
+		}
+
+		if len(rec.calls) != 2 {
+			t.Errorf("calls - got: %v, want: 2 calls", rec.calls)
+		}
+	})
+
+	// Test Case 3: Keys being loaded by GetOrLoad are not loaded again
+	t.Run("coalesced with GetOrLoad", func(t *testing.T) {
+		c := NewSync()
+		rec := &batchRecorder{}
+		release := make(chan struct{})
+		done := make(chan struct{})
+
+		go func() {
+			c.GetOrLoad(context.Background(), "k1", func(ctx context.Context) (any, time.Duration, error) {
+				<-release
+
+				return "loaded", 0, nil
+			})
+			close(done)
+		}()
+
+		waitForWaiters(t, c.Cache, "k1", 1)
+
+		go func() {
+			waitForWaiters(t, c.Cache, "k1", 2)
+			close(release)
+		}()
+
+		values, err := c.GetManyOrLoad(context.Background(), []string{"k1", "k2"}, rec.load)
+		<-done
+
+		if err != nil || values["k1"] != "loaded" || values["k2"] != "v-k2" {
+			t.Errorf("GetManyOrLoad - got: %v, %v, want: map[k1:loaded k2:v-k2], nil", values, err)
+		}
+
+		if len(rec.calls) != 1 || fmt.Sprint(rec.calls[0]) != "[k2]" {
+			t.Errorf("calls - got: %v, want: [[k2]]", rec.calls)
+		}
+	})
+
+	// Test Case 4: Keys missing after a batch was abandoned are loaded in a new batch
+	t.Run("abandoned batch", func(t *testing.T) {
+		c := NewSync(WithBatchWindow(50 * time.Millisecond))
+
+		loader := func(ctx context.Context, keys []string) (map[string]any, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			values := make(map[string]any, len(keys))
+			for _, key := range keys {
+				values[key] = "v-" + key
+			}
+
+			return values, nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		if _, err := c.GetManyOrLoad(ctx, []string{"a"}, loader); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("GetManyOrLoad a: err - got: %v, want: context.DeadlineExceeded", err)
+		}
+
+		if values, err := c.GetManyOrLoad(context.Background(), []string{"b"}, loader); err != nil || values["b"] != "v-b" {
+			t.Errorf("GetManyOrLoad b - got: %v, %v, want: map[b:v-b], nil", values, err)
+		}
+	})
+}
+
+func TestCacheGetManyOrLoadInline(t *testing.T) {
+	// Setup
+	c := New(WithBatchWindow(time.Hour), WithMaxBatchSize(2))
+	c.Set("k1", "value1")
+
+	// Test Case 1: Missing keys are loaded by the caller without waiting for the batch window
+	t.Run("load", func(t *testing.T) {
+		rec := &batchRecorder{missing: map[string]bool{"k4": true}}
+		values, err := c.GetManyOrLoad(context.Background(), []string{"k1", "k2", "k3", "k4"}, rec.load)
+
+		var batchErr *BatchError[string]
+		if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 || !errors.Is(batchErr.Errors["k4"], ErrKeyNotFound) {
+			t.Errorf("GetManyOrLoad: err - got: %v, want: k4: ErrKeyNotFound", err)
+		}
+
+		if len(values) != 3 || values["k2"] != "v-k2" || values["k3"] != "v-k3" {
+			t.Errorf("values - got: %v, want: map[k1:value1 k2:v-k2 k3:v-k3]", values)
+		}
+
+		if fmt.Sprint(rec.calls) != "[[k2 k3] [k4]]" {
+			t.Errorf("calls - got: %v, want: [[k2 k3] [k4]]", rec.calls)
+		}
+
+		if c.Len() != 3 || len(c.flights) != 0 {
+			t.Errorf("Len, loads in progress - got: %d, %d, want: 3, 0", c.Len(), len(c.flights))
+		}
+	})
+
+	// Test Case 2: Context cancellation
+	t.Run("cancel", func(t *testing.T) {
+		rec := &batchRecorder{}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		values, err := c.GetManyOrLoad(ctx, []string{"k1", "k5"}, rec.load)
+
+		if !errors.Is(err, context.Canceled) || len(values) != 1 {
+			t.Errorf("GetManyOrLoad - got: %v, %v, want: map[k1:value1], context.Canceled", values, err)
+		}
+
+		if rec.callCount() != 0 {
+			t.Errorf("calls - got: %v, want: none", rec.calls)
+		}
+	})
+}
+
+func TestShardedCacheGetManyOrLoad(t *testing.T) {
+	sc := NewSharded(WithShards(8))
+	rec := &batchRecorder{}
+	keys := make([]string, 20)
+
+	for i := range keys {
+		keys[i] = fmt.Sprintf("k%d", i)
+	}
+
+	values, err := sc.GetManyOrLoad(context.Background(), keys, rec.load)
+
+	if err != nil || len(values) != 20 {
+		t.Errorf("GetManyOrLoad - got: %d values, %v, want: 20 values, nil", len(values), err)
+	}
+
+	if len(rec.calls) != 1 || len(rec.calls[0]) != 20 {
+		t.Errorf("calls - got: %v, want: a single call with 20 keys", rec.calls)
+	}
+
+	if sc.Len() != 20 {
+		t.Errorf("Len - got: %d, want: 20", sc.Len())
+	}
+}
@@ -0,0 +1,42 @@
+package gocache
+
+import "time"
+
+// Clock is the source of the current time and of the timers of a cache.
+// The cache uses the system clock by default, a custom clock can be set with [WithClock].
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// AfterFunc waits for the duration to elapse and then calls f in its own goroutine.
+	// It returns a [Timer] that can be used to cancel the call or to wait for another duration.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a timer created by a [Clock], calling a function once it fires.
+// It is implemented by [time.Timer].
+type Timer interface {
+	// Stop prevents the timer from firing.
+	// It returns true if the call stops the timer, false if the timer has already fired or been stopped.
+	Stop() bool
+	// Reset changes the timer to fire after the duration.
+	// It returns true if the timer had been active, false if the timer had fired or been stopped.
+	Reset(d time.Duration) bool
+}
+
+// realClock is a [Clock] backed by the system clock.
+type realClock struct{}
+
+// Now returns the current system time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc calls f in its own goroutine after the duration, using [time.AfterFunc].
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// now returns the current time of the cache's clock, in UTC.
+func (c *TypedCache[K, V]) now() time.Time {
+	return c.clock.Now().UTC()
+}
@@ -0,0 +1,230 @@
+package gocache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Codec encodes values to bytes along with a tag identifying their type, and decodes them back to values
+// of the same type, so that values stored as any can be persisted or sent over the network faithfully.
+// It is used by SaveTo and LoadFrom when set with [WithCodec].
+type Codec interface {
+	// Marshal encodes the provided value, returning the tag of its type, which is not empty, and its encoding.
+	Marshal(value any) (tag string, data []byte, err error)
+	// Unmarshal decodes a value of the type of the provided tag from its encoding.
+	Unmarshal(tag string, data []byte) (any, error)
+}
+
+// nilTag is the type tag of the nil values.
+const nilTag = "nil"
+
+// encodingCodec is a [Codec] encoding the values with an encoding of the standard library, their type
+// tag being the tag they are registered with, see [RegisterType].
+type encodingCodec struct {
+	marshal   func(value any) ([]byte, error)
+	unmarshal func(data []byte, ptr any) error
+}
+
+// GobCodec returns a [Codec] encoding the values with encoding/gob.
+// The types of the values must be registered with [RegisterType], the basic types are registered already.
+func GobCodec() Codec {
+	return encodingCodec{marshal: gobMarshal, unmarshal: gobUnmarshal}
+}
+
+// JSONCodec returns a [Codec] encoding the values with encoding/json.
+// The types of the values must be registered with [RegisterType], the basic types are registered already.
+func JSONCodec() Codec {
+	return encodingCodec{marshal: json.Marshal, unmarshal: json.Unmarshal}
+}
+
+// Marshal encodes the provided value, returning the tag its type is registered with and its encoding.
+func (c encodingCodec) Marshal(value any) (string, []byte, error) {
+	if value == nil {
+		return nilTag, nil, nil
+	}
+
+	tag, err := typeTag(value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := c.marshal(value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return tag, data, nil
+}
+
+// Unmarshal decodes a value of the type registered with the provided tag from its encoding.
+func (c encodingCodec) Unmarshal(tag string, data []byte) (any, error) {
+	if tag == nilTag {
+		return nil, nil
+	}
+
+	typ, err := tagType(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	ptr := reflect.New(typ)
+	if err := c.unmarshal(data, ptr.Interface()); err != nil {
+		return nil, err
+	}
+
+	return ptr.Elem().Interface(), nil
+}
+
+// gobMarshal encodes the provided value with encoding/gob.
+func gobMarshal(value any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// gobUnmarshal decodes the provided encoding/gob encoding into the value pointed to.
+func gobUnmarshal(data []byte, ptr any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(ptr)
+}
+
+// bytesCodec is a [Codec] storing the byte slices and the strings as is.
+type bytesCodec struct{}
+
+// BytesCodec returns a [Codec] storing the byte slices and the strings as is, without encoding them.
+// It fails to encode values of any other type.
+func BytesCodec() Codec {
+	return bytesCodec{}
+}
+
+// Marshal returns the bytes of the provided byte slice or string.
+func (bytesCodec) Marshal(value any) (string, []byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return "[]byte", v, nil
+	case string:
+		return "string", []byte(v), nil
+	case nil:
+		return nilTag, nil, nil
+	default:
+		return "", nil, fmt.Errorf("bytes codec cannot encode a value of type %T", value)
+	}
+}
+
+// Unmarshal returns the byte slice or the string of the provided bytes.
+func (bytesCodec) Unmarshal(tag string, data []byte) (any, error) {
+	switch tag {
+	case "[]byte":
+		return append([]byte{}, data...), nil
+	case "string":
+		return string(data), nil
+	case nilTag:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("bytes codec cannot decode a value of type %q", tag)
+	}
+}
+
+// registry holds the types registered with [RegisterType], by tag and by type.
+var registry = struct {
+	sync.RWMutex
+	types map[string]reflect.Type
+	tags  map[reflect.Type]string
+}{
+	types: make(map[string]reflect.Type),
+	tags:  make(map[reflect.Type]string),
+}
+
+// RegisterType registers the type of the provided value under the provided tag, for [GobCodec] and
+// [JSONCodec] to encode the values of that type with the tag and decode them back to that type.
+// It is typically called from an init function, with the zero value of the type, for instance
+// gocache.RegisterType("user", User{}). The tag must be stable across the programs sharing the encoded values.
+//
+// The basic types, byte slices, string slices, time.Time and time.Duration are registered with the name
+// of their type, such as "int64", "[]byte" or "time.Time".
+// It panics if the tag or the type is already registered with another type or tag, if the tag is empty
+// or if the value is nil.
+func RegisterType(tag string, value any) {
+	if tag == "" || tag == nilTag {
+		panic(fmt.Sprintf("gocache: invalid type tag %q", tag))
+	}
+
+	if value == nil {
+		panic("gocache: cannot register the type of a nil value")
+	}
+
+	typ := reflect.TypeOf(value)
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if t, ok := registry.types[tag]; ok && t != typ {
+		panic(fmt.Sprintf("gocache: registering type %s under tag %q, already registered for type %s", typ, tag, t))
+	}
+
+	if t, ok := registry.tags[typ]; ok && t != tag {
+		panic(fmt.Sprintf("gocache: registering type %s under tag %q, already registered under tag %q", typ, tag, t))
+	}
+
+	registry.types[tag] = typ
+	registry.tags[typ] = tag
+}
+
+// typeTag returns the tag the type of the provided value is registered with.
+func typeTag(value any) (string, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	tag, ok := registry.tags[reflect.TypeOf(value)]
+	if !ok {
+		return "", fmt.Errorf("%w %T", ErrUnregisteredType, value)
+	}
+
+	return tag, nil
+}
+
+// tagType returns the type registered with the provided tag.
+func tagType(tag string) (reflect.Type, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	typ, ok := registry.types[tag]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnregisteredType, tag)
+	}
+
+	return typ, nil
+}
+
+func init() {
+	for tag, value := range map[string]any{
+		"bool":          false,
+		"string":        "",
+		"int":           0,
+		"int8":          int8(0),
+		"int16":         int16(0),
+		"int32":         int32(0),
+		"int64":         int64(0),
+		"uint":          uint(0),
+		"uint8":         uint8(0),
+		"uint16":        uint16(0),
+		"uint32":        uint32(0),
+		"uint64":        uint64(0),
+		"float32":       float32(0),
+		"float64":       float64(0),
+		"[]byte":        []byte(nil),
+		"[]string":      []string(nil),
+		"time.Time":     time.Time{},
+		"time.Duration": time.Duration(0),
+	} {
+		RegisterType(tag, value)
+	}
+}
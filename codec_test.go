@@ -0,0 +1,205 @@
+package gocache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// codecUser is a user struct registered for the codec tests.
+type codecUser struct {
+	Name    string
+	Age     int
+	Created time.Time
+}
+
+func init() {
+	RegisterType("gocache.codecUser", codecUser{})
+}
+
+func TestCodecs(t *testing.T) {
+	// Setup
+	created := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	values := []any{
+		nil,
+		"value",
+		42,
+		int64(42),
+		3.5,
+		true,
+		[]byte("bytes"),
+		[]string{"a", "b"},
+		created,
+		time.Minute,
+		codecUser{Name: "alice", Age: 30, Created: created},
+	}
+
+	codecs := []struct {
+		label string
+		codec Codec
+	}{
+		{"gob", GobCodec()},
+		{"json", JSONCodec()},
+	}
+
+	// Test Case 1: Values are decoded to their type
+	for _, tc := range codecs {
+		t.Run(tc.label, func(t *testing.T) {
+			for _, value := range values {
+				tag, data, err := tc.codec.Marshal(value)
+				if err != nil || tag == "" {
+					t.Fatalf("Marshal %v - got: %q, %v, want: tag, nil", value, tag, err)
+				}
+
+				decoded, err := tc.codec.Unmarshal(tag, data)
+				if err != nil || !reflect.DeepEqual(decoded, value) {
+					t.Errorf("Unmarshal %v - got: %#v, %v, want: %#v, nil", value, decoded, err, value)
+				}
+			}
+		})
+	}
+
+	// Test Case 2: Values of unregistered types are rejected
+	t.Run("unregistered", func(t *testing.T) {
+		type unregistered struct{ Name string }
+
+		if _, _, err := JSONCodec().Marshal(unregistered{}); !errors.Is(err, ErrUnregisteredType) {
+			t.Errorf("Marshal - got: %v, want: ErrUnregisteredType", err)
+		}
+
+		if _, err := GobCodec().Unmarshal("unknown", nil); !errors.Is(err, ErrUnregisteredType) {
+			t.Errorf("Unmarshal - got: %v, want: ErrUnregisteredType", err)
+		}
+	})
+
+	// Test Case 3: The bytes codec stores byte slices and strings as is
+	t.Run("bytes", func(t *testing.T) {
+		for _, value := range []any{nil, "value", []byte("bytes")} {
+			tag, data, err := BytesCodec().Marshal(value)
+			if err != nil {
+				t.Fatalf("Marshal %v - got: %v, want: nil", value, err)
+			}
+
+			decoded, err := BytesCodec().Unmarshal(tag, data)
+			if err != nil || !reflect.DeepEqual(decoded, value) {
+				t.Errorf("Unmarshal %v - got: %#v, %v, want: %#v, nil", value, decoded, err, value)
+			}
+		}
+
+		if tag, data, _ := BytesCodec().Marshal([]byte("raw")); tag != "[]byte" || string(data) != "raw" {
+			t.Errorf("Marshal raw - got: %q, %q, want: []byte, raw", tag, data)
+		}
+
+		if _, _, err := BytesCodec().Marshal(42); err == nil {
+			t.Errorf("Marshal 42 - got: nil, want: error")
+		}
+	})
+}
+
+func TestRegisterType(t *testing.T) {
+	// Setup
+	mustPanic := func(t *testing.T, f func()) {
+		t.Helper()
+
+		defer func() {
+			if recover() == nil {
+				t.Errorf("RegisterType - got: no panic, want: panic")
+			}
+		}()
+
+		f()
+	}
+
+	// Test Case 1: A type is registered again under the same tag
+	t.Run("same tag", func(t *testing.T) {
+		RegisterType("gocache.codecUser", codecUser{})
+	})
+
+	// Test Case 2: Conflicting registrations panic
+	t.Run("conflicts", func(t *testing.T) {
+		mustPanic(t, func() { RegisterType("gocache.codecUser", struct{ Name string }{}) })
+		mustPanic(t, func() { RegisterType("other", codecUser{}) })
+		mustPanic(t, func() { RegisterType("", codecUser{}) })
+		mustPanic(t, func() { RegisterType("nil value", nil) })
+	})
+}
+
+func TestCacheSnapshotCodec(t *testing.T) {
+	// Setup
+	c := New(WithCodec(JSONCodec()))
+	c.Set("k1", codecUser{Name: "alice", Age: 30})
+	c.Set("k2", 42)
+	c.Set("k3", nil)
+
+	var buf bytes.Buffer
+	if err := c.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo - got: %v, want: nil", err)
+	}
+
+	// Test Case 1: Values of any type are saved and loaded with the codec
+	t.Run("round trip", func(t *testing.T) {
+		loaded := NewSharded(WithCodec(JSONCodec()))
+		if err := loaded.LoadFrom(bytes.NewReader(buf.Bytes())); err != nil {
+			t.Fatalf("LoadFrom - got: %v, want: nil", err)
+		}
+
+		if value, err := loaded.Get("k1"); err != nil || value != (codecUser{Name: "alice", Age: 30}) {
+			t.Errorf("Get k1 - got: %#v, %v, want: alice, nil", value, err)
+		}
+
+		if value, err := loaded.Get("k2"); err != nil || value != 42 {
+			t.Errorf("Get k2 - got: %#v, %v, want: 42, nil", value, err)
+		}
+
+		if value, err := loaded.Get("k3"); err != nil || value != nil {
+			t.Errorf("Get k3 - got: %#v, %v, want: nil, nil", value, err)
+		}
+	})
+
+	// Test Case 2: A snapshot saved with a codec is not loaded without one
+	t.Run("without codec", func(t *testing.T) {
+		loaded := New()
+
+		if err := loaded.LoadFrom(bytes.NewReader(buf.Bytes())); err == nil || loaded.Len() != 0 {
+			t.Errorf("LoadFrom - got: %v, %d keys, want: error, 0 keys", err, loaded.Len())
+		}
+	})
+
+	// Test Case 3: Values that cannot be encoded by the codec are rejected
+	t.Run("unsupported value", func(t *testing.T) {
+		c := New(WithCodec(BytesCodec()))
+		c.Set("k1", 42)
+
+		if err := c.SaveTo(&bytes.Buffer{}); err == nil {
+			t.Errorf("SaveTo - got: nil, want: error")
+		}
+	})
+
+	// Test Case 4: Snapshots of the version 1 are loaded
+	t.Run("version 1", func(t *testing.T) {
+		c := New()
+		c.Set("k1", "value1")
+
+		var buf bytes.Buffer
+		c.SaveTo(&buf)
+
+		data := buf.Bytes()
+		body := data[:len(data)-snapshotChecksumSize]
+		binary.BigEndian.PutUint16(body[len(snapshotMagic):], 1)
+		binary.BigEndian.PutUint32(data[len(body):], crc32.Checksum(body, snapshotTable))
+
+		loaded := New(WithCodec(GobCodec()))
+		if err := loaded.LoadFrom(bytes.NewReader(data)); err != nil {
+			t.Fatalf("LoadFrom - got: %v, want: nil", err)
+		}
+
+		if value, err := loaded.Get("k1"); err != nil || value != "value1" {
+			t.Errorf("Get k1 - got: %v, %v, want: value1, nil", value, err)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package gocache
+
+import (
+	"context"
+	"reflect"
+)
+
+// Sizer defines a function type that estimates the cost, usually in bytes, of a value stored in the cache.
+type Sizer func(value any) int64
+
+// DefaultSizer estimates the cost of a value as its size in bytes.
+// The cost of a string or a []byte is its length, the cost of a number or a bool is its size in
+// memory, and the cost of any other value is the shallow size of its type, which does not account
+// for the memory it references. Custom types can be estimated with a [Sizer] set by [WithSizer],
+// falling back to DefaultSizer for the common types.
+func DefaultSizer(value any) int64 {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(v))
+	case []byte:
+		return int64(len(v))
+	case bool, int8, uint8:
+		return 1
+	case int16, uint16:
+		return 2
+	case int32, uint32, float32:
+		return 4
+	case int, int64, uint, uint64, uintptr, float64, complex64:
+		return 8
+	case complex128:
+		return 16
+	default:
+		return int64(reflect.TypeOf(value).Size())
+	}
+}
+
+// Cost returns the total cost of the entries stored in the cache.
+func (c *TypedCache[K, V]) Cost() int64 {
+	obs := c.observe(context.Background(), OpCost, "")
+	c.rLock()
+	cost := c.cost
+	c.rUnlock()
+	obs.done(OutcomeSuccess)
+
+	return cost
+}
@@ -0,0 +1,254 @@
+package gocache
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var defaultSizerTestCases = []struct {
+	label    string
+	value    any
+	expected int64
+}{
+	{"nil", nil, 0},
+	{"string", "value", 5},
+	{"bytes", []byte("bytes value"), 11},
+	{"bool", true, 1},
+	{"int32", int32(1), 4},
+	{"int", 1, 8},
+	{"float64", 1.5, 8},
+	{"struct", struct{ a, b int64 }{1, 2}, 16},
+}
+
+func TestDefaultSizer(t *testing.T) {
+	for _, tc := range defaultSizerTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			if cost := DefaultSizer(tc.value); cost != tc.expected {
+				t.Errorf("cost - got: %d, want: %d", cost, tc.expected)
+			}
+		})
+	}
+}
+
+func TestCacheSetWithCost(t *testing.T) {
+	// Setup
+	c := New(WithMaxCost(100))
+
+	// Test Case 1: Explicit and estimated costs
+	t.Run("explicit and estimated costs", func(t *testing.T) {
+		if err := c.SetWithCost("k1", "value1", 40, -1); err != nil {
+			t.Errorf("SetWithCost k1: err - got: %v, want: nil", err)
+		}
+
+		if err := c.Set("k2", "value2"); err != nil {
+			t.Errorf("Set k2: err - got: %v, want: nil", err)
+		}
+
+		if cost := c.Cost(); cost != 46 {
+			t.Errorf("Cost - got: %d, want: 46", cost)
+		}
+	})
+
+	// Test Case 2: Cost larger than the maximum cost
+	t.Run("cost too large", func(t *testing.T) {
+		if err := c.SetWithCost("k3", "value3", 101, -1); !errors.Is(err, ErrCostTooLarge) {
+			t.Errorf("SetWithCost k3: err - got: %v, want: ErrCostTooLarge", err)
+		}
+	})
+
+	// Test Case 3: Cache full without an eviction policy
+	t.Run("cache full", func(t *testing.T) {
+		if err := c.SetWithCost("k3", "value3", 60, -1); !errors.Is(err, ErrCacheFull) {
+			t.Errorf("SetWithCost k3: err - got: %v, want: ErrCacheFull", err)
+		}
+
+		if cost := c.Cost(); cost != 46 {
+			t.Errorf("Cost - got: %d, want: 46", cost)
+		}
+	})
+
+	// Test Case 4: Replacing an entry replaces its cost
+	t.Run("replace cost", func(t *testing.T) {
+		if err := c.SetWithCost("k1", "value1", 90, -1); err != nil {
+			t.Errorf("SetWithCost k1: err - got: %v, want: nil", err)
+		}
+
+		if cost := c.Cost(); cost != 96 {
+			t.Errorf("Cost - got: %d, want: 96", cost)
+		}
+	})
+
+	// Test Case 5: Removing entries releases their cost
+	t.Run("remove releases cost", func(t *testing.T) {
+		c.Delete("k1")
+
+		if cost := c.Cost(); cost != 6 {
+			t.Errorf("Cost - got: %d, want: 6", cost)
+		}
+
+		c.SetWithCost("k3", "value3", 10, 50*time.Millisecond)
+
+		time.Sleep(100 * time.Millisecond)
+
+		if cost := c.Cost(); cost != 6 {
+			t.Errorf("Cost after expiry - got: %d, want: 6", cost)
+		}
+
+		c.Clear()
+
+		if cost := c.Cost(); cost != 0 {
+			t.Errorf("Cost after clear - got: %d, want: 0", cost)
+		}
+	})
+}
+
+func TestCacheCostEviction(t *testing.T) {
+	c := New(WithMaxCost(100), WithEvictionPolicy(LRU))
+	c.SetWithCost("k1", "value1", 30, -1)
+	c.SetWithCost("k2", "value2", 30, -1)
+	c.SetWithCost("k3", "value3", 30, -1)
+	c.Get("k1")
+
+	// Test Case 1: Evict until the new entry fits
+	t.Run("evict until fits", func(t *testing.T) {
+		if err := c.SetWithCost("k4", "value4", 50, -1); err != nil {
+			t.Errorf("SetWithCost k4: err - got: %v, want: nil", err)
+		}
+
+		if c.Has("k2") || c.Has("k3") {
+			t.Error("Has k2, k3 - got: true, want: false")
+		}
+
+		if cost := c.Cost(); cost != 80 {
+			t.Errorf("Cost - got: %d, want: 80", cost)
+		}
+	})
+
+	// Test Case 2: Growing an existing entry evicts the others
+	t.Run("grow existing entry", func(t *testing.T) {
+		if err := c.SetWithCost("k4", "value4", 100, -1); err != nil {
+			t.Errorf("SetWithCost k4: err - got: %v, want: nil", err)
+		}
+
+		if keys := c.Keys(); len(keys) != 1 || keys[0] != "k4" {
+			t.Errorf("keys - got: %v, want: [k4]", keys)
+		}
+
+		if cost := c.Cost(); cost != 100 {
+			t.Errorf("Cost - got: %d, want: 100", cost)
+		}
+	})
+}
+
+func TestCacheCostEvictionUpdatedKey(t *testing.T) {
+	// Setup
+	rec := &removalRecorder{}
+	c := New(WithMaxCost(10), WithEvictionPolicy(LRU), WithOnEvicted(rec.onEvicted))
+	c.SetWithCost("k1", "value1", 4, -1)
+	c.SetWithCost("k2", "value2", 4, -1)
+
+	// Test Case 1: The updated key is not evicted to make room for itself
+	t.Run("least recently used", func(t *testing.T) {
+		if err := c.SetWithCost("k1", "new value1", 8, -1); err != nil {
+			t.Errorf("SetWithCost k1: err - got: %v, want: nil", err)
+		}
+
+		if got, want := rec.take(), []string{"k2=value2:evicted", "k1=value1:replaced"}; !equalStrings(got, want) {
+			t.Errorf("removals - got: %v, want: %v", got, want)
+		}
+
+		if cost := c.Cost(); cost != 8 {
+			t.Errorf("Cost - got: %d, want: 8", cost)
+		}
+	})
+
+	// Test Case 2: The updated key is still tracked by the eviction policy
+	t.Run("still tracked", func(t *testing.T) {
+		c.SetWithCost("k3", "value3", 4, -1)
+
+		if got, want := rec.take(), []string{"k1=new value1:evicted"}; !equalStrings(got, want) {
+			t.Errorf("removals - got: %v, want: %v", got, want)
+		}
+	})
+}
+
+func TestCacheSizer(t *testing.T) {
+	type blob struct {
+		data []byte
+	}
+
+	c := New(WithSizer(func(value any) int64 {
+		if b, ok := value.(blob); ok {
+			return int64(len(b.data))
+		}
+
+		return DefaultSizer(value)
+	}))
+
+	c.Set("k1", blob{data: make([]byte, 1024)})
+	c.Set("k2", "value2")
+
+	if cost := c.Cost(); cost != 1030 {
+		t.Errorf("Cost - got: %d, want: 1030", cost)
+	}
+}
+
+func TestShardedCacheCost(t *testing.T) {
+	// Test Case 1: The maximum cost is enforced across all the shards
+	t.Run("max cost", func(t *testing.T) {
+		sc := NewSharded(WithShards(4), WithMaxCost(400), WithEvictionPolicy(LRU))
+
+		for i := 0; i < 100; i++ {
+			if err := sc.SetWithCost(string(rune('a'+i%26))+string(rune('a'+i/26)), i, 10, -1); err != nil {
+				t.Errorf("SetWithCost %d: err - got: %v, want: nil", i, err)
+			}
+		}
+
+		if cost := sc.Cost(); cost > 400 {
+			t.Errorf("Cost - got: %d, want: at most 400", cost)
+		}
+	})
+
+	// Test Case 2: An entry may cost more than the maximum cost divided by the number of shards
+	t.Run("larger than a shard", func(t *testing.T) {
+		sc := NewSharded(WithMaxCost(100))
+
+		if err := sc.SetWithCost("k1", "value1", 50, -1); err != nil {
+			t.Errorf("SetWithCost k1: err - got: %v, want: nil", err)
+		}
+
+		if err := sc.SetWithCost("k2", "value2", 101, -1); !errors.Is(err, ErrCostTooLarge) {
+			t.Errorf("SetWithCost k2: err - got: %v, want: ErrCostTooLarge", err)
+		}
+
+		if cost := sc.Cost(); cost != 50 {
+			t.Errorf("Cost - got: %d, want: 50", cost)
+		}
+	})
+
+	// Test Case 3: The maximum cost is not exceeded when it is not a multiple of the number of shards
+	t.Run("not a multiple", func(t *testing.T) {
+		sc := NewSharded(WithMaxCost(10))
+		stored := 0
+
+		for i := 0; i < 32; i++ {
+			if err := sc.SetWithCost(fmt.Sprintf("k%d", i), i, 1, -1); err == nil {
+				stored++
+			} else if !errors.Is(err, ErrCacheFull) {
+				t.Errorf("SetWithCost k%d: err - got: %v, want: nil or ErrCacheFull", i, err)
+			}
+		}
+
+		if cost := sc.Cost(); cost != 10 || stored != 10 {
+			t.Errorf("Cost, stored - got: %d, %d, want: 10, 10", cost, stored)
+		}
+
+		sc.Delete("k0")
+
+		if cost := sc.Cost(); cost != 9 {
+			t.Errorf("Cost - got: %d, want: 9", cost)
+		}
+	})
+}
@@ -1,11 +1,35 @@
 package gocache
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrKeyNotFound is an error for when a key doesn't exist in the cache.
 	ErrKeyNotFound = errors.New("key not found")
 
+	// ErrNegativeCached is an error for when a key is cached as not found, see [WithNegativeTtl].
+	// It wraps [ErrKeyNotFound].
+	ErrNegativeCached = fmt.Errorf("%w, cached as missing", ErrKeyNotFound)
+
 	// ErrCacheFull is an error for when the cache has reached the maximum allowed number of items.
 	ErrCacheFull = errors.New("the cache is full")
+
+	// ErrCostTooLarge is an error for when the cost of an entry is larger than the maximum allowed cost of the cache.
+	ErrCostTooLarge = errors.New("the cost is larger than the maximum cost of the cache")
+
+	// ErrInvalidSnapshot is an error for when the data loaded by LoadFrom is not a snapshot of a cache, or is truncated.
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+	// ErrSnapshotVersion is an error for when the snapshot loaded by LoadFrom was saved in an unsupported format version.
+	ErrSnapshotVersion = errors.New("unsupported snapshot version")
+
+	// ErrSnapshotChecksum is an error for when the snapshot loaded by LoadFrom does not match its checksum,
+	// that is it was corrupted.
+	ErrSnapshotChecksum = errors.New("snapshot checksum mismatch")
+
+	// ErrUnregisteredType is an error for when a [Codec] encodes or decodes a value of a type that is not
+	// registered, see [RegisterType].
+	ErrUnregisteredType = errors.New("unregistered type")
 )
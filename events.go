@@ -0,0 +1,269 @@
+package gocache
+
+import (
+	"fmt"
+	"sync"
+)
+
+// defaultEventBuffer is the default number of events buffered for a subscriber.
+const defaultEventBuffer = 64
+
+// EventType is the type of a change made to the cache, streamed to its subscribers.
+type EventType int
+
+const (
+	// EventSet means a value was set for a new key.
+	EventSet EventType = iota
+	// EventUpdate means the value of an existing key was replaced.
+	EventUpdate
+	// EventDelete means the entry was deleted, including by a call to Clear.
+	EventDelete
+	// EventExpire means the entry was removed because it expired.
+	EventExpire
+	// EventEvict means the entry was evicted by the eviction policy.
+	EventEvict
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventSet:
+		return "set"
+	case EventUpdate:
+		return "update"
+	case EventDelete:
+		return "delete"
+	case EventExpire:
+		return "expire"
+	case EventEvict:
+		return "evict"
+	default:
+		return fmt.Sprintf("EventType(%d)", int(t))
+	}
+}
+
+// TypedEvent is a change made to a [TypedCache], streamed to its subscribers.
+type TypedEvent[K comparable, V any] struct {
+	// Type is the type of the change.
+	Type EventType
+	// Key is the key of the changed entry.
+	Key K
+	// OldValue is the value of the entry before the change, it is the zero value of V for EventSet.
+	OldValue V
+	// NewValue is the value of the entry after the change, it is the zero value of V unless the type
+	// is EventSet or EventUpdate.
+	NewValue V
+}
+
+// Event is a change made to a [Cache], streamed to its subscribers.
+type Event = TypedEvent[string, any]
+
+// OverflowPolicy defines what happens to the events of a subscriber whose buffer is full.
+type OverflowPolicy int
+
+const (
+	// DropEvents drops the events that do not fit in the buffer of the subscriber, so that a slow
+	// subscriber never slows the cache down.
+	DropEvents OverflowPolicy = iota
+	// BlockOnFull blocks the goroutine changing the cache, once its lock is released, until the
+	// subscriber has room for the event, so that no event is lost.
+	BlockOnFull
+)
+
+// SubscribeOptFunc defines a function type for configuring a subscription to the events of a cache.
+type SubscribeOptFunc func(*subscribeConfig)
+
+// subscribeConfig holds the configurations of a subscription.
+type subscribeConfig struct {
+	// buffer is the number of events buffered in the channel of the subscriber.
+	buffer int
+	// overflow defines what happens to the events when the buffer is full.
+	overflow OverflowPolicy
+}
+
+// WithEventBuffer returns a [SubscribeOptFunc] that sets the number of events buffered for the subscriber.
+// It defaults to 64, negative sizes are ignored.
+func WithEventBuffer(size int) SubscribeOptFunc {
+	return func(c *subscribeConfig) {
+		if size > -1 {
+			c.buffer = size
+		}
+	}
+}
+
+// WithOverflowPolicy returns a [SubscribeOptFunc] that sets what happens to the events of the subscriber
+// when its buffer is full. It defaults to [DropEvents].
+func WithOverflowPolicy(policy OverflowPolicy) SubscribeOptFunc {
+	return func(c *subscribeConfig) {
+		c.overflow = policy
+	}
+}
+
+// subscriber is a subscription to the events of one or more caches.
+type subscriber[K comparable, V any] struct {
+	// pattern is the glob pattern the keys of the events must match, empty means all the keys.
+	pattern  string
+	overflow OverflowPolicy
+	events   chan TypedEvent[K, V]
+	// done is closed when the subscription is cancelled, to unblock a pending send.
+	done chan struct{}
+
+	// mu guards the events channel from being closed while an event is sent.
+	mu     sync.Mutex
+	closed bool
+	once   sync.Once
+}
+
+// newSubscriber creates a new subscriber to the events whose keys match the glob pattern.
+func newSubscriber[K comparable, V any](pattern string, opts ...SubscribeOptFunc) *subscriber[K, V] {
+	cfg := subscribeConfig{
+		buffer:   defaultEventBuffer,
+		overflow: DropEvents,
+	}
+
+	for _, fn := range opts {
+		fn(&cfg)
+	}
+
+	return &subscriber[K, V]{
+		pattern:  pattern,
+		overflow: cfg.overflow,
+		events:   make(chan TypedEvent[K, V], cfg.buffer),
+		done:     make(chan struct{}),
+	}
+}
+
+// send sends the event to the subscriber if its key matches, according to the overflow policy.
+func (s *subscriber[K, V]) send(e TypedEvent[K, V]) {
+	if s.pattern != "" && !matchGlob(s.pattern, keyString(e.Key)) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	if s.overflow == BlockOnFull {
+		select {
+		case s.events <- e:
+		case <-s.done:
+		}
+
+		return
+	}
+
+	select {
+	case s.events <- e:
+	default:
+	}
+}
+
+// close closes the events channel of the subscriber, unblocking any pending send first.
+func (s *subscriber[K, V]) close() {
+	s.once.Do(func() {
+		close(s.done)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.closed = true
+		close(s.events)
+	})
+}
+
+// Subscribe streams the changes made to the cache whose keys match the provided glob pattern, where '*'
+// matches any sequence of characters and '?' matches any single character, e.g. "user:*" matches all
+// the keys starting with "user:". An empty pattern matches all the keys.
+// The events are sent once the cache's lock is released, in the order of the changes made by each goroutine.
+// By default, 64 events are buffered and the events that do not fit are dropped, see [WithEventBuffer]
+// and [WithOverflowPolicy].
+// It returns the channel of events and a function cancelling the subscription, which closes the channel.
+func (c *TypedCache[K, V]) Subscribe(pattern string, opts ...SubscribeOptFunc) (<-chan TypedEvent[K, V], func()) {
+	s := newSubscriber[K, V](pattern, opts...)
+	c.subscribe(s)
+
+	return s.events, func() {
+		c.unsubscribe(s)
+		s.close()
+	}
+}
+
+// subscribe adds the subscriber to the cache.
+// The list of subscribers is copied on write, so that it can be read without the lock once released.
+func (c *TypedCache[K, V]) subscribe(s *subscriber[K, V]) {
+	c.lock()
+	defer c.unlock()
+
+	subscribers := make([]*subscriber[K, V], len(c.subscribers), len(c.subscribers)+1)
+	copy(subscribers, c.subscribers)
+	c.subscribers = append(subscribers, s)
+}
+
+// unsubscribe removes the subscriber from the cache.
+func (c *TypedCache[K, V]) unsubscribe(s *subscriber[K, V]) {
+	c.lock()
+	defer c.unlock()
+
+	subscribers := make([]*subscriber[K, V], 0, len(c.subscribers))
+
+	for _, sub := range c.subscribers {
+		if sub != s {
+			subscribers = append(subscribers, sub)
+		}
+	}
+
+	c.subscribers = subscribers
+}
+
+// published records the event, to send to the subscribers once the write lock is released.
+// Nothing is recorded if the cache has no subscribers.
+func (c *TypedCache[K, V]) published(e TypedEvent[K, V]) {
+	if len(c.subscribers) == 0 {
+		return
+	}
+
+	c.events = append(c.events, e)
+}
+
+// publish sends each of the provided events to the provided subscribers, in order.
+// It must be called without holding the lock of the cache.
+func publish[K comparable, V any](subscribers []*subscriber[K, V], events []TypedEvent[K, V]) {
+	for _, e := range events {
+		for _, s := range subscribers {
+			s.send(e)
+		}
+	}
+}
+
+// matchGlob reports whether the string matches the glob pattern, where '*' matches any sequence of
+// characters and '?' matches any single character.
+func matchGlob(pattern, s string) bool {
+	p, i := 0, 0
+	// star and next are the positions of the last '*' in the pattern and of the string it backtracks to.
+	star, next := -1, 0
+
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == s[i]):
+			p++
+			i++
+		case p < len(pattern) && pattern[p] == '*':
+			star, next = p, i
+			p++
+		case star != -1:
+			next++
+			p, i = star+1, next
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
@@ -0,0 +1,231 @@
+package gocache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// drain returns the events buffered in the channel, formatted as type:key:old:new.
+func drain(events <-chan Event) []string {
+	var got []string
+
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return got
+			}
+
+			got = append(got, fmt.Sprintf("%s:%s:%v:%v", e.Type, e.Key, e.OldValue, e.NewValue))
+		default:
+			return got
+		}
+	}
+}
+
+func TestCacheSubscribe(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := New(WithClock(clk), WithMaxKeys(2), WithEvictionPolicy(FIFO), WithDeleteOnExpire(false))
+	events, cancel := c.Subscribe("")
+
+	// Test Case 1: Set and update
+	t.Run("set and update", func(t *testing.T) {
+		c.Set("k1", "value1")
+		c.Set("k1", "new value1")
+
+		want := []string{"set:k1:<nil>:value1", "update:k1:value1:new value1"}
+		if got := drain(events); !equalStrings(got, want) {
+			t.Errorf("events - got: %v, want: %v", got, want)
+		}
+	})
+
+	// Test Case 2: Delete, evict and expire
+	t.Run("removals", func(t *testing.T) {
+		c.Set("k2", "value2")
+		c.Set("k3", "value3")
+		c.Delete("k2")
+		c.ChangeTtl("k3", time.Minute)
+		clk.advance(2 * time.Minute)
+		c.PurgeExpired()
+
+		want := []string{
+			"set:k2:<nil>:value2",
+			"evict:k1:new value1:<nil>",
+			"set:k3:<nil>:value3",
+			"delete:k2:value2:<nil>",
+			"expire:k3:value3:<nil>",
+		}
+		if got := drain(events); !equalStrings(got, want) {
+			t.Errorf("events - got: %v, want: %v", got, want)
+		}
+	})
+
+	// Test Case 3: Cancel closes the channel
+	t.Run("cancel", func(t *testing.T) {
+		cancel()
+		cancel()
+		c.Set("k4", "value4")
+
+		if _, ok := <-events; ok {
+			t.Errorf("channel open - got: true, want: false")
+		}
+
+		if len(c.subscribers) != 0 {
+			t.Errorf("subscribers - got: %d, want: 0", len(c.subscribers))
+		}
+	})
+}
+
+func TestCacheSubscribeFilter(t *testing.T) {
+	// Setup
+	c := New()
+	users, cancelUsers := c.Subscribe("user:*")
+	sessions, cancelSessions := c.Subscribe("session:?")
+
+	defer cancelUsers()
+	defer cancelSessions()
+
+	c.Set("user:1", 1)
+	c.Set("user:2/profile", 2)
+	c.Set("session:1", 1)
+	c.Set("session:12", 12)
+	c.Set("other", 0)
+
+	// Test Case 1: Prefix pattern
+	t.Run("prefix", func(t *testing.T) {
+		want := []string{"set:user:1:<nil>:1", "set:user:2/profile:<nil>:2"}
+		if got := drain(users); !equalStrings(got, want) {
+			t.Errorf("events - got: %v, want: %v", got, want)
+		}
+	})
+
+	// Test Case 2: Single character pattern
+	t.Run("single character", func(t *testing.T) {
+		want := []string{"set:session:1:<nil>:1"}
+		if got := drain(sessions); !equalStrings(got, want) {
+			t.Errorf("events - got: %v, want: %v", got, want)
+		}
+	})
+}
+
+func TestCacheSubscribeOverflow(t *testing.T) {
+	// Test Case 1: Events are dropped when the buffer is full
+	t.Run("drop", func(t *testing.T) {
+		c := New()
+		events, cancel := c.Subscribe("", WithEventBuffer(2))
+
+		defer cancel()
+
+		for i := 0; i < 5; i++ {
+			c.Set(fmt.Sprintf("k%d", i), i)
+		}
+
+		if got := drain(events); len(got) != 2 {
+			t.Errorf("events - got: %v, want: 2 events", got)
+		}
+	})
+
+	// Test Case 2: Changes block until the subscriber receives the events
+	t.Run("block", func(t *testing.T) {
+		c := NewSync()
+		events, cancel := c.Subscribe("", WithEventBuffer(0), WithOverflowPolicy(BlockOnFull))
+
+		defer cancel()
+
+		var wg sync.WaitGroup
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 5; i++ {
+				c.Set(fmt.Sprintf("k%d", i), i)
+			}
+		}()
+
+		for i := 0; i < 5; i++ {
+			if e := <-events; e.Key != fmt.Sprintf("k%d", i) {
+				t.Errorf("event %d key - got: %s, want: k%d", i, e.Key, i)
+			}
+
+			// the cache is not locked while the event is pending
+			c.Has("k0")
+		}
+
+		wg.Wait()
+	})
+
+	// Test Case 3: Cancelling unblocks the pending changes
+	t.Run("cancel blocked", func(t *testing.T) {
+		c := NewSync()
+		_, cancel := c.Subscribe("", WithEventBuffer(0), WithOverflowPolicy(BlockOnFull))
+		done := make(chan struct{})
+
+		go func() {
+			c.Set("k1", "value1")
+			close(done)
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Errorf("Set - got: blocked, want: unblocked")
+		}
+	})
+}
+
+func TestShardedCacheSubscribe(t *testing.T) {
+	sc := NewSharded(WithShards(4))
+	events, cancel := sc.Subscribe("k*")
+
+	for i := 0; i < 10; i++ {
+		sc.Set(fmt.Sprintf("k%d", i), i)
+	}
+
+	sc.Set("other", 0)
+
+	if got := drain(events); len(got) != 10 {
+		t.Errorf("events - got: %v, want: 10 events", got)
+	}
+
+	cancel()
+
+	if _, ok := <-events; ok {
+		t.Errorf("channel open - got: true, want: false")
+	}
+}
+
+var matchGlobTestCases = []struct {
+	pattern  string
+	s        string
+	expected bool
+}{
+	{"*", "", true},
+	{"*", "anything", true},
+	{"user:*", "user:42", true},
+	{"user:*", "users", false},
+	{"*:name", "user:1:name", true},
+	{"a*b*c", "axxbyyc", true},
+	{"a*b*c", "axxbyy", false},
+	{"k?", "k1", true},
+	{"k?", "k12", false},
+	{"exact", "exact", true},
+	{"exact", "exactly", false},
+}
+
+func TestMatchGlob(t *testing.T) {
+	for _, tc := range matchGlobTestCases {
+		t.Run(tc.pattern+"/"+tc.s, func(t *testing.T) {
+			if got := matchGlob(tc.pattern, tc.s); got != tc.expected {
+				t.Errorf("matchGlob - got: %v, want: %v", got, tc.expected)
+			}
+		})
+	}
+}
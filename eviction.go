@@ -0,0 +1,27 @@
+package gocache
+
+// EvictionPolicy tracks the usage of the cache entries and selects the entry to evict when the cache
+// has reached its maximum number of keys.
+// The cache notifies the policy of every change to its entries, keys are passed as values of the
+// key type of the cache.
+// A policy is only used by a single cache and is always called under the cache's lock, so it does
+// not need to be safe for concurrent use.
+type EvictionPolicy interface {
+	// OnInsert is called when a new key is added to the cache.
+	OnInsert(key any)
+	// OnAccess is called when the value of a key is read from the cache.
+	OnAccess(key any)
+	// OnUpdate is called when the value of an existing key is replaced.
+	OnUpdate(key any)
+	// OnRemove is called when a key is removed from the cache, whether deleted or expired.
+	OnRemove(key any)
+	// Victim returns the key to evict from the cache to make room for a new one, and stops tracking it.
+	// It returns false if there is no key to evict.
+	Victim() (key any, ok bool)
+}
+
+// NoEviction is the constructor of the default policy, it returns a nil [EvictionPolicy] which means that
+// no entry is evicted and new keys are rejected with [ErrCacheFull] when the cache is full.
+func NoEviction() EvictionPolicy {
+	return nil
+}
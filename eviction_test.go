@@ -0,0 +1,89 @@
+package gocache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// recordingPolicy is a custom [EvictionPolicy] that records the notifications it receives and
+// evicts the keys in the order they were inserted.
+type recordingPolicy struct {
+	events []string
+	keys   []any
+}
+
+func (p *recordingPolicy) OnInsert(key any) {
+	p.events = append(p.events, "insert "+key.(string))
+	p.keys = append(p.keys, key)
+}
+
+func (p *recordingPolicy) OnAccess(key any) {
+	p.events = append(p.events, "access "+key.(string))
+}
+
+func (p *recordingPolicy) OnUpdate(key any) {
+	p.events = append(p.events, "update "+key.(string))
+}
+
+func (p *recordingPolicy) OnRemove(key any) {
+	p.events = append(p.events, "remove "+key.(string))
+
+	for i, k := range p.keys {
+		if k == key {
+			p.keys = append(p.keys[:i], p.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *recordingPolicy) Victim() (any, bool) {
+	if len(p.keys) == 0 {
+		return nil, false
+	}
+
+	key := p.keys[0]
+	p.keys = p.keys[1:]
+	p.events = append(p.events, "victim "+key.(string))
+
+	return key, true
+}
+
+func TestCacheCustomEvictionPolicy(t *testing.T) {
+	p := &recordingPolicy{}
+	c := New(WithMaxKeys(2), WithEvictionPolicy(func() EvictionPolicy { return p }))
+
+	c.Set("k1", "value1")
+	c.Set("k2", "value2")
+	c.Get("k1")
+	c.Get("nokey")
+	c.Set("k1", "new value1")
+	c.Set("k3", "value3")
+	c.Delete("k3")
+	c.SetWithTtl("k4", "value4", 50*time.Millisecond)
+	c.GetAndDelete("k1")
+
+	time.Sleep(100 * time.Millisecond)
+	// the entries of a non-concurrent cache are deleted by the next operation once the timer fired
+	c.Len()
+
+	expected := []string{
+		"insert k1",
+		"insert k2",
+		"access k1",
+		"update k1",
+		"victim k1",
+		"insert k3",
+		"remove k3",
+		"insert k4",
+		"remove k4",
+	}
+
+	if !reflect.DeepEqual(p.events, expected) {
+		t.Errorf("events - got: %v, want: %v", p.events, expected)
+	}
+
+	if _, err := c.Get("k2"); err != nil {
+		t.Errorf("Get k2: err - got: %v, want: nil", err)
+	}
+}
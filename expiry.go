@@ -0,0 +1,120 @@
+package gocache
+
+import (
+	"container/heap"
+	"time"
+)
+
+// defaultExpiryResolution is the default granularity at which entries are deleted on expiry.
+const defaultExpiryResolution = 10 * time.Millisecond
+
+// expiryHeap is a min-heap of cache entries ordered by expiry date, implementing [heap.Interface].
+// Each entry keeps its position in the heap, so that it can be removed or moved when its expiry changes.
+type expiryHeap[K comparable, V any] []*cacheValue[K, V]
+
+func (h expiryHeap[K, V]) Len() int {
+	return len(h)
+}
+
+func (h expiryHeap[K, V]) Less(i, j int) bool {
+	return h[i].expiryDate.Before(h[j].expiryDate)
+}
+
+func (h expiryHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap[K, V]) Push(x any) {
+	val := x.(*cacheValue[K, V])
+	val.index = len(*h)
+	*h = append(*h, val)
+}
+
+func (h *expiryHeap[K, V]) Pop() any {
+	old := *h
+	n := len(old) - 1
+	val := old[n]
+	old[n] = nil
+	val.index = -1
+	*h = old[:n]
+
+	return val
+}
+
+// schedule schedules the deletion of the entry at its expiry date, or moves it if already scheduled.
+func (c *TypedCache[K, V]) schedule(val *cacheValue[K, V]) {
+	if val.index >= 0 {
+		heap.Fix(&c.expiries, val.index)
+	} else {
+		heap.Push(&c.expiries, val)
+	}
+
+	c.armExpiryTimer()
+}
+
+// unschedule cancels the deletion of the entry on expiry, if scheduled.
+func (c *TypedCache[K, V]) unschedule(val *cacheValue[K, V]) {
+	if val.index >= 0 {
+		heap.Remove(&c.expiries, val.index)
+	}
+}
+
+// reschedule schedules the deletion of the entry on expiry after its TTL is set, or the cleanup of the
+// expired entries if they are not deleted on expiry.
+func (c *TypedCache[K, V]) reschedule(val *cacheValue[K, V]) {
+	if val.ttl > 0 && c.deleteOnExpire {
+		c.schedule(val)
+	} else {
+		c.unschedule(val)
+	}
+
+	if val.ttl > 0 {
+		c.armCleanupTimer()
+	}
+}
+
+// armExpiryTimer sets the expiry timer to fire when the earliest scheduled entry is due, rounded up to
+// the expiry resolution so that entries expiring close to each other are deleted in a single batch.
+// The timer is left as is if it already fires earlier.
+func (c *TypedCache[K, V]) armExpiryTimer() {
+	if len(c.expiries) == 0 {
+		return
+	}
+
+	at := c.expiries[0].expiryDate.Add(c.staleFor())
+	if rounded := at.Truncate(c.expiryResolution); rounded.Before(at) {
+		at = rounded.Add(c.expiryResolution)
+	}
+
+	if !c.expiryAt.IsZero() && !c.expiryAt.After(at) {
+		return
+	}
+
+	c.expiryAt = at
+	delay := at.Sub(c.now())
+
+	if c.expiryTimer == nil {
+		c.expiryTimer = c.clock.AfterFunc(delay, c.onTimer(&c.expiryDue, c.expireDue))
+	} else {
+		c.expiryTimer.Reset(delay)
+	}
+}
+
+// expireDue deletes all the scheduled entries that have expired, then sets the expiry timer for the next ones.
+// Expired entries are kept for the time they can be returned stale, see [WithStaleWhileRevalidate].
+func (c *TypedCache[K, V]) expireDue() {
+	c.lock()
+	defer c.unlock()
+
+	c.expiryAt = time.Time{}
+	now := c.now().Add(-c.staleFor())
+
+	for len(c.expiries) > 0 && !c.expiries[0].expiryDate.After(now) {
+		val := c.expiries[0]
+		c.remove(val.key, val, Expired)
+	}
+
+	c.armExpiryTimer()
+}
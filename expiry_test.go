@@ -0,0 +1,176 @@
+package gocache
+
+import (
+	"container/heap"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const expiryBenchKeys = 1000000
+
+func TestExpiryHeap(t *testing.T) {
+	now := time.Now()
+	h := expiryHeap[string, int]{}
+
+	for i, offset := range []int{5, 1, 4, 2, 3} {
+		heap.Push(&h, &cacheValue[string, int]{key: strconv.Itoa(i), expiryDate: now.Add(time.Duration(offset) * time.Second), index: -1})
+	}
+
+	// Test Case 1: Entries keep track of their position
+	t.Run("positions", func(t *testing.T) {
+		for i, val := range h {
+			if val.index != i {
+				t.Errorf("index of %s - got: %d, want: %d", val.key, val.index, i)
+			}
+		}
+	})
+
+	// Test Case 2: Entries are popped by expiry date
+	t.Run("order", func(t *testing.T) {
+		heap.Remove(&h, h[len(h)-1].index)
+
+		prev := time.Time{}
+		for h.Len() > 0 {
+			val := heap.Pop(&h).(*cacheValue[string, int])
+
+			if val.expiryDate.Before(prev) {
+				t.Errorf("expiry date of %s - got: before the previous one", val.key)
+			}
+
+			if val.index != -1 {
+				t.Errorf("index of popped %s - got: %d, want: -1", val.key, val.index)
+			}
+
+			prev = val.expiryDate
+		}
+	})
+}
+
+func TestCacheExpiryScheduling(t *testing.T) {
+	// Setup
+	c := New(WithExpiryResolution(20 * time.Millisecond))
+
+	// Test Case 1: Only entries deleted on expiry are scheduled
+	t.Run("scheduled entries", func(t *testing.T) {
+		c.Set("k1", "value1")
+		c.SetWithTtl("k2", "value2", 50*time.Millisecond)
+		c.SetWithTtl("k3", "value3", 60*time.Millisecond)
+		c.SetWithTtl("k4", "value4", time.Hour)
+
+		if c.expiries.Len() != 3 {
+			t.Errorf("scheduled entries - got: %d, want: 3", c.expiries.Len())
+		}
+
+		if c.data["k1"].index != -1 {
+			t.Errorf("index of k1 - got: %d, want: -1", c.data["k1"].index)
+		}
+	})
+
+	// Test Case 2: Entries expiring close to each other are deleted in a single batch
+	t.Run("batch expiry", func(t *testing.T) {
+		time.Sleep(100 * time.Millisecond)
+		// the entries of a non-concurrent cache are deleted by the next operation once the timer fired
+		c.Len()
+
+		if len(c.data) != 2 || c.expiries.Len() != 1 {
+			t.Errorf("entries - got: %d (%d scheduled), want: 2 (1 scheduled)", len(c.data), c.expiries.Len())
+		}
+	})
+
+	// Test Case 3: Changing the TTL reschedules the entry
+	t.Run("reschedule", func(t *testing.T) {
+		c.ChangeTtl("k4", 30*time.Millisecond)
+		c.ChangeTtl("k1", time.Hour)
+		c.ChangeTtl("k1", 0)
+
+		if c.data["k1"].index != -1 {
+			t.Errorf("index of k1 - got: %d, want: -1", c.data["k1"].index)
+		}
+
+		time.Sleep(80 * time.Millisecond)
+
+		if c.Has("k4") || len(c.data) != 1 {
+			t.Errorf("entries - got: %d, want: 1", len(c.data))
+		}
+	})
+
+	// Test Case 4: Deleted and replaced entries are unscheduled
+	t.Run("unschedule", func(t *testing.T) {
+		c.SetWithTtl("k5", "value5", time.Hour)
+		c.SetWithTtl("k6", "value6", time.Hour)
+		c.Delete("k5")
+		c.Set("k6", "new value6")
+
+		if c.expiries.Len() != 0 {
+			t.Errorf("scheduled entries - got: %d, want: 0", c.expiries.Len())
+		}
+	})
+}
+
+func TestCacheExpiryNotDeleted(t *testing.T) {
+	c := New(WithDeleteOnExpire(false))
+	c.SetWithTtl("k1", "value1", 10*time.Millisecond)
+
+	if c.expiries.Len() != 0 || c.expiryTimer != nil {
+		t.Errorf("scheduled entries - got: %d, want: 0", c.expiries.Len())
+	}
+}
+
+// BenchmarkExpiry1M compares the memory and time it takes to set a million entries with a TTL using
+// a timer per entry, as the cache used to, against the expiry heap.
+// Run it with -benchmem to compare the bytes and allocations per million entries.
+func BenchmarkExpiry1M(b *testing.B) {
+	keys := make([]string, expiryBenchKeys)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	b.Run("timer per entry", func(b *testing.B) {
+		type timerValue struct {
+			value      any
+			expiryDate time.Time
+			timer      *time.Timer
+		}
+
+		for i := 0; i < b.N; i++ {
+			var mu sync.Mutex
+			data := make(map[string]*timerValue)
+
+			for _, key := range keys {
+				key := key
+
+				mu.Lock()
+				data[key] = &timerValue{
+					expiryDate: time.Now().UTC().Add(time.Hour),
+					timer: time.AfterFunc(time.Hour, func() {
+						mu.Lock()
+						delete(data, key)
+						mu.Unlock()
+					}),
+				}
+				mu.Unlock()
+			}
+
+			b.StopTimer()
+			for _, val := range data {
+				val.timer.Stop()
+			}
+			b.StartTimer()
+		}
+	})
+
+	b.Run("expiry heap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c := NewSync()
+			for _, key := range keys {
+				c.SetWithTtl(key, nil, time.Hour)
+			}
+
+			b.StopTimer()
+			c.Clear()
+			b.StartTimer()
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package gocache
+
+import "container/list"
+
+// fifoPolicy is an [EvictionPolicy] that evicts the oldest inserted entry.
+type fifoPolicy struct {
+	order    *list.List
+	elements map[any]*list.Element
+}
+
+// FIFO creates an [EvictionPolicy] that evicts the entries in the order they were inserted, reads
+// and updates of an entry do not change its position.
+func FIFO() EvictionPolicy {
+	return &fifoPolicy{
+		order:    list.New(),
+		elements: make(map[any]*list.Element),
+	}
+}
+
+// OnInsert adds the key as the newest one.
+func (p *fifoPolicy) OnInsert(key any) {
+	if _, ok := p.elements[key]; ok {
+		return
+	}
+
+	p.elements[key] = p.order.PushFront(key)
+}
+
+// OnAccess does nothing, the order of insertion is not affected by reads.
+func (p *fifoPolicy) OnAccess(key any) {}
+
+// OnUpdate does nothing, the order of insertion is not affected by updates.
+func (p *fifoPolicy) OnUpdate(key any) {}
+
+// OnRemove stops tracking the key.
+func (p *fifoPolicy) OnRemove(key any) {
+	if elem, ok := p.elements[key]; ok {
+		p.order.Remove(elem)
+		delete(p.elements, key)
+	}
+}
+
+// Victim returns the oldest inserted key.
+func (p *fifoPolicy) Victim() (any, bool) {
+	elem := p.order.Back()
+
+	if elem == nil {
+		return nil, false
+	}
+
+	p.order.Remove(elem)
+	delete(p.elements, elem.Value)
+
+	return elem.Value, true
+}
@@ -0,0 +1,36 @@
+package gocache
+
+import "testing"
+
+func TestFIFOPolicy(t *testing.T) {
+	p := FIFO()
+	p.OnInsert("k1")
+	p.OnInsert("k2")
+	p.OnInsert("k3")
+	p.OnAccess("k1")
+	p.OnUpdate("k1")
+	p.OnRemove("k2")
+
+	for _, want := range []string{"k1", "k3"} {
+		if key, ok := p.Victim(); !ok || key != want {
+			t.Errorf("Victim - got: %v, %v, want: %s, true", key, ok, want)
+		}
+	}
+
+	if key, ok := p.Victim(); ok {
+		t.Errorf("Victim - got: %v, %v, want: nil, false", key, ok)
+	}
+}
+
+func TestCacheFIFOEviction(t *testing.T) {
+	c := New(WithMaxKeys(2), WithEvictionPolicy(FIFO))
+	c.Set("k1", "value1")
+	c.Set("k2", "value2")
+	c.Get("k1")
+
+	c.Set("k3", "value3")
+
+	if c.Has("k1") {
+		t.Error("Has k1 - got: true, want: false")
+	}
+}
@@ -0,0 +1,160 @@
+package gocachetest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/khchehab/gocache"
+)
+
+// Clock is a fake [gocache.Clock] whose time only moves when advanced.
+// Timers created by the clock fire synchronously, in the goroutine advancing the clock, once their
+// time is reached, so that a cache using the clock deletes its expired entries before Advance returns.
+// A Clock is safe for concurrent use by multiple goroutines.
+type Clock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*timer
+}
+
+// NewClock creates a new [Clock] set to the provided time.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// AfterFunc creates a timer that calls f once the clock is advanced by the duration or more.
+func (c *Clock) AfterFunc(d time.Duration, f func()) gocache.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &timer{clock: c, at: c.now.Add(d), f: f, active: true}
+	c.timers = append(c.timers, t)
+
+	return t
+}
+
+// Advance moves the time of the clock forward by the duration, firing the timers that are due in
+// order of their time. The time of the clock is set to the time of each timer before it fires.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	c.mu.Unlock()
+
+	c.Set(target)
+}
+
+// Set moves the time of the clock to the provided time, firing the timers that are due in order of
+// their time. Setting a time before the current time of the clock does not fire any timer.
+func (c *Clock) Set(now time.Time) {
+	for {
+		c.mu.Lock()
+
+		next := c.next(now)
+		if next == nil {
+			if now.After(c.now) {
+				c.now = now
+			}
+
+			c.mu.Unlock()
+
+			return
+		}
+
+		if next.at.After(c.now) {
+			c.now = next.at
+		}
+
+		next.active = false
+		c.mu.Unlock()
+
+		// the timer fires outside the lock, since its function may create or reset timers
+		next.f()
+	}
+}
+
+// Timers returns the number of timers that have not fired and have not been stopped.
+func (c *Clock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count := 0
+
+	for _, t := range c.timers {
+		if t.active {
+			count++
+		}
+	}
+
+	return count
+}
+
+// next returns the earliest active timer due at the provided time, or nil if there is none.
+// Timers that are not active anymore are dropped along the way.
+func (c *Clock) next(now time.Time) *timer {
+	var next *timer
+
+	active := c.timers[:0]
+
+	for _, t := range c.timers {
+		if !t.active {
+			continue
+		}
+
+		active = append(active, t)
+
+		if !t.at.After(now) && (next == nil || t.at.Before(next.at)) {
+			next = t
+		}
+	}
+
+	for i := len(active); i < len(c.timers); i++ {
+		c.timers[i] = nil
+	}
+
+	c.timers = active
+
+	return next
+}
+
+// timer is a [gocache.Timer] created by a [Clock].
+type timer struct {
+	clock  *Clock
+	at     time.Time
+	f      func()
+	active bool
+}
+
+// Stop prevents the timer from firing.
+func (t *timer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	wasActive := t.active
+	t.active = false
+
+	return wasActive
+}
+
+// Reset changes the timer to fire once the clock is advanced by the duration from its current time.
+func (t *timer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	wasActive := t.active
+	t.at = t.clock.now.Add(d)
+
+	if !wasActive {
+		t.active = true
+		t.clock.timers = append(t.clock.timers, t)
+	}
+
+	return wasActive
+}
@@ -0,0 +1,154 @@
+package gocachetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/khchehab/gocache"
+)
+
+var epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func TestClockAdvance(t *testing.T) {
+	// Setup
+	clk := NewClock(epoch)
+
+	var fired []int
+
+	clk.AfterFunc(30*time.Second, func() { fired = append(fired, 3) })
+	clk.AfterFunc(10*time.Second, func() { fired = append(fired, 1) })
+	stopped := clk.AfterFunc(20*time.Second, func() { fired = append(fired, 2) })
+
+	// Test Case 1: Time does not move on its own
+	t.Run("now", func(t *testing.T) {
+		if now := clk.Now(); !now.Equal(epoch) {
+			t.Errorf("Now - got: %v, want: %v", now, epoch)
+		}
+	})
+
+	// Test Case 2: Stopped timers do not fire
+	t.Run("stop", func(t *testing.T) {
+		if !stopped.Stop() {
+			t.Errorf("Stop - got: false, want: true")
+		}
+
+		if stopped.Stop() {
+			t.Errorf("Stop again - got: true, want: false")
+		}
+
+		if clk.Timers() != 2 {
+			t.Errorf("Timers - got: %d, want: 2", clk.Timers())
+		}
+	})
+
+	// Test Case 3: Due timers fire in order
+	t.Run("advance", func(t *testing.T) {
+		clk.Advance(15 * time.Second)
+
+		if len(fired) != 1 || fired[0] != 1 {
+			t.Errorf("fired - got: %v, want: [1]", fired)
+		}
+
+		clk.Advance(time.Minute)
+
+		if len(fired) != 2 || fired[1] != 3 {
+			t.Errorf("fired - got: %v, want: [1 3]", fired)
+		}
+
+		if want := epoch.Add(75 * time.Second); !clk.Now().Equal(want) {
+			t.Errorf("Now - got: %v, want: %v", clk.Now(), want)
+		}
+	})
+
+	// Test Case 4: Reset timers fire again
+	t.Run("reset", func(t *testing.T) {
+		if stopped.Reset(time.Second) {
+			t.Errorf("Reset - got: true, want: false")
+		}
+
+		clk.Advance(time.Second)
+
+		if len(fired) != 3 || fired[2] != 2 {
+			t.Errorf("fired - got: %v, want: [1 3 2]", fired)
+		}
+
+		if clk.Timers() != 0 {
+			t.Errorf("Timers - got: %d, want: 0", clk.Timers())
+		}
+	})
+}
+
+func TestClockCacheExpiry(t *testing.T) {
+	// Setup
+	clk := NewClock(epoch)
+	c := gocache.New(gocache.WithClock(clk), gocache.WithStdTtl(time.Hour))
+	c.Set("k1", "value1")
+	c.SetWithTtl("k2", "value2", 2*time.Hour)
+	c.SetWithTtl("k3", "value3", 0)
+
+	// Test Case 1: Entries live until their TTL
+	t.Run("before TTL", func(t *testing.T) {
+		clk.Advance(59 * time.Minute)
+
+		if c.Len() != 3 {
+			t.Errorf("Len - got: %d, want: 3", c.Len())
+		}
+	})
+
+	// Test Case 2: Entries are deleted once their TTL is reached
+	t.Run("after TTL", func(t *testing.T) {
+		clk.Advance(time.Minute + time.Second)
+
+		if _, err := c.Get("k1"); !errors.Is(err, gocache.ErrKeyNotFound) {
+			t.Errorf("Get k1: err - got: %v, want: ErrKeyNotFound", err)
+		}
+
+		if c.Len() != 2 {
+			t.Errorf("Len - got: %d, want: 2", c.Len())
+		}
+
+		clk.Advance(time.Hour)
+
+		if keys := c.Keys(); len(keys) != 1 || keys[0] != "k3" {
+			t.Errorf("Keys - got: %v, want: [k3]", keys)
+		}
+
+		if clk.Timers() != 0 {
+			t.Errorf("Timers - got: %d, want: 0", clk.Timers())
+		}
+	})
+}
+
+func TestClockCacheCleanup(t *testing.T) {
+	// Setup
+	clk := NewClock(epoch)
+	c := gocache.New(
+		gocache.WithClock(clk),
+		gocache.WithDeleteOnExpire(false),
+		gocache.WithCleanupInterval(time.Minute),
+	)
+	c.SetWithTtl("k1", "value1", 30*time.Second)
+
+	// Test Case 1: Expired entries remain until the cleanup
+	t.Run("before cleanup", func(t *testing.T) {
+		clk.Advance(45 * time.Second)
+
+		if c.Len() != 1 || c.LiveLen() != 0 {
+			t.Errorf("Len, LiveLen - got: %d, %d, want: 1, 0", c.Len(), c.LiveLen())
+		}
+	})
+
+	// Test Case 2: Expired entries are purged by the cleanup
+	t.Run("after cleanup", func(t *testing.T) {
+		clk.Advance(15 * time.Second)
+
+		if c.Len() != 0 {
+			t.Errorf("Len - got: %d, want: 0", c.Len())
+		}
+
+		if clk.Timers() != 0 {
+			t.Errorf("Timers - got: %d, want: 0", clk.Timers())
+		}
+	})
+}
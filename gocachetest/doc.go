@@ -0,0 +1,3 @@
+// Package gocachetest provides utilities for testing code that uses the [gocache] package, such as a
+// [Clock] to control the expiration of cache entries without waiting for it.
+package gocachetest
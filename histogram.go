@@ -0,0 +1,191 @@
+package gocache
+
+import (
+	"context"
+	"math"
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// histogramSubBits is the number of significant bits of the durations recorded by a [Histogram],
+	// which bounds the relative error of the quantiles to 1/2^(histogramSubBits-1).
+	histogramSubBits = 6
+	// histogramSubBuckets is the number of buckets per power of 2, the durations below it are recorded exactly.
+	histogramSubBuckets = 1 << histogramSubBits
+	// histogramMaxBits is the number of bits of the largest duration tracked, about 4.9 hours, larger
+	// durations are recorded in the last bucket.
+	histogramMaxBits = 44
+	// histogramBuckets is the number of buckets of a [Histogram].
+	histogramBuckets = histogramSubBuckets + (histogramMaxBits-histogramSubBits)*histogramSubBuckets/2
+)
+
+// Histogram records durations in logarithmic buckets, in the manner of an HDR histogram, to compute
+// their quantiles with a relative error of about 3% in constant memory. Durations up to about 4.9 hours
+// are tracked, the quantiles falling among larger durations are the largest duration recorded.
+//
+// A Histogram is safe for concurrent use by multiple goroutines. The zero value is an empty histogram
+// ready to use.
+type Histogram struct {
+	counts [histogramBuckets]int64
+	count  int64
+	sum    int64
+	max    int64
+}
+
+// Record records the provided duration in the histogram, negative durations are recorded as 0.
+func (h *Histogram) Record(d time.Duration) {
+	v := int64(d)
+	if v < 0 {
+		v = 0
+	}
+
+	atomic.AddInt64(&h.counts[histogramBucket(v)], 1)
+	atomic.AddInt64(&h.count, 1)
+	atomic.AddInt64(&h.sum, v)
+
+	for {
+		max := atomic.LoadInt64(&h.max)
+		if v <= max || atomic.CompareAndSwapInt64(&h.max, max, v) {
+			return
+		}
+	}
+}
+
+// Count returns the number of durations recorded in the histogram.
+func (h *Histogram) Count() int64 {
+	return atomic.LoadInt64(&h.count)
+}
+
+// Mean returns the mean of the durations recorded in the histogram, it is 0 if there are none.
+func (h *Histogram) Mean() time.Duration {
+	count := atomic.LoadInt64(&h.count)
+	if count == 0 {
+		return 0
+	}
+
+	return time.Duration(atomic.LoadInt64(&h.sum) / count)
+}
+
+// Max returns the largest duration recorded in the histogram, it is 0 if there are none.
+func (h *Histogram) Max() time.Duration {
+	return time.Duration(atomic.LoadInt64(&h.max))
+}
+
+// Quantile returns the duration below which the provided fraction of the recorded durations fall, for
+// instance 0.99 for the 99th percentile. The fraction is clamped to [0, 1].
+// It is the upper bound of the bucket of the quantile, and it is 0 if there are no durations recorded.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	var counts [histogramBuckets]int64
+
+	total := int64(0)
+
+	for i := range counts {
+		counts[i] = atomic.LoadInt64(&h.counts[i])
+		total += counts[i]
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	rank := int64(math.Ceil(math.Max(0, math.Min(1, q)) * float64(total)))
+	if rank < 1 {
+		rank = 1
+	}
+
+	max := atomic.LoadInt64(&h.max)
+	seen := int64(0)
+
+	for i, count := range counts {
+		seen += count
+
+		if seen >= rank {
+			// the last bucket holds the durations too large to track, bounded by the largest one only
+			if upper := histogramUpper(i); upper < max && i < histogramBuckets-1 {
+				return time.Duration(upper)
+			}
+
+			break
+		}
+	}
+
+	return time.Duration(max)
+}
+
+// Reset removes all the durations recorded in the histogram.
+func (h *Histogram) Reset() {
+	for i := range h.counts {
+		atomic.StoreInt64(&h.counts[i], 0)
+	}
+
+	atomic.StoreInt64(&h.count, 0)
+	atomic.StoreInt64(&h.sum, 0)
+	atomic.StoreInt64(&h.max, 0)
+}
+
+// histogramBucket returns the index of the bucket of the provided non-negative value.
+func histogramBucket(v int64) int {
+	if v < histogramSubBuckets {
+		return int(v)
+	}
+
+	if v >= 1<<histogramMaxBits {
+		v = 1<<histogramMaxBits - 1
+	}
+
+	// the values with the same most significant bits share a bucket
+	shift := bits.Len64(uint64(v)) - histogramSubBits
+	mantissa := int(v >> shift)
+
+	return histogramSubBuckets + (shift-1)*histogramSubBuckets/2 + mantissa - histogramSubBuckets/2
+}
+
+// histogramUpper returns the largest value of the bucket at the provided index.
+func histogramUpper(i int) int64 {
+	if i < histogramSubBuckets {
+		return int64(i)
+	}
+
+	i -= histogramSubBuckets
+	shift := i/(histogramSubBuckets/2) + 1
+	mantissa := int64(i%(histogramSubBuckets/2) + histogramSubBuckets/2)
+
+	return (mantissa+1)<<shift - 1
+}
+
+// LatencyObserver is an [Observer] recording the duration of each operation on a cache in a [Histogram]
+// per operation, whatever its outcome.
+type LatencyObserver struct {
+	histograms map[Op]*Histogram
+}
+
+// NewLatencyObserver creates a new [LatencyObserver] instance with empty histograms.
+func NewLatencyObserver() *LatencyObserver {
+	o := &LatencyObserver{histograms: make(map[Op]*Histogram, len(ops))}
+
+	for _, op := range ops {
+		o.histograms[op] = &Histogram{}
+	}
+
+	return o
+}
+
+// Before returns the provided context as is.
+func (o *LatencyObserver) Before(ctx context.Context, _ Op, _ string) context.Context {
+	return ctx
+}
+
+// After records the duration of the operation in its histogram.
+func (o *LatencyObserver) After(_ context.Context, op Op, _ string, _ Outcome, d time.Duration) {
+	if h, ok := o.histograms[op]; ok {
+		h.Record(d)
+	}
+}
+
+// Histogram returns the histogram of the durations of the provided operation, or nil if the operation
+// is unknown.
+func (o *LatencyObserver) Histogram(op Op) *Histogram {
+	return o.histograms[op]
+}
@@ -0,0 +1,153 @@
+package gocache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHistogram(t *testing.T) {
+	// Setup
+	var h Histogram
+
+	// Test Case 1: An empty histogram
+	t.Run("empty", func(t *testing.T) {
+		if h.Count() != 0 || h.Mean() != 0 || h.Max() != 0 || h.Quantile(0.5) != 0 {
+			t.Errorf("empty histogram - got: %d, %v, %v, %v, want: 0", h.Count(), h.Mean(), h.Max(), h.Quantile(0.5))
+		}
+	})
+
+	// Test Case 2: Small durations are recorded exactly
+	t.Run("exact", func(t *testing.T) {
+		for i := 1; i <= 10; i++ {
+			h.Record(time.Duration(i))
+		}
+
+		if got := h.Quantile(0.5); got != 5 {
+			t.Errorf("p50 - got: %v, want: 5ns", got)
+		}
+
+		if h.Count() != 10 || h.Max() != 10 || h.Mean() != 5 {
+			t.Errorf("count, max, mean - got: %d, %v, %v, want: 10, 10ns, 5ns", h.Count(), h.Max(), h.Mean())
+		}
+
+		h.Reset()
+	})
+
+	// Test Case 3: Quantiles are within the relative error of the histogram
+	t.Run("quantiles", func(t *testing.T) {
+		for i := 1; i <= 1000; i++ {
+			h.Record(time.Duration(i) * time.Microsecond)
+		}
+
+		for _, tc := range []struct {
+			q    float64
+			want time.Duration
+		}{
+			{0.5, 500 * time.Microsecond},
+			{0.9, 900 * time.Microsecond},
+			{0.99, 990 * time.Microsecond},
+			{1, 1000 * time.Microsecond},
+		} {
+			got := h.Quantile(tc.q)
+
+			if got < tc.want || float64(got-tc.want) > float64(tc.want)/32 {
+				t.Errorf("quantile %v - got: %v, want: %v", tc.q, got, tc.want)
+			}
+		}
+	})
+
+	// Test Case 4: Reset empties the histogram
+	t.Run("reset", func(t *testing.T) {
+		h.Reset()
+
+		if h.Count() != 0 || h.Max() != 0 || h.Quantile(1) != 0 {
+			t.Errorf("reset histogram - got: %d, %v, %v, want: 0", h.Count(), h.Max(), h.Quantile(1))
+		}
+	})
+
+	// Test Case 5: Durations too large are recorded as the largest duration
+	t.Run("too large", func(t *testing.T) {
+		h.Record(24 * time.Hour)
+
+		if got := h.Quantile(1); got != 24*time.Hour {
+			t.Errorf("p100 - got: %v, want: 24h", got)
+		}
+
+		h.Reset()
+	})
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	// Test Case 1: Each value falls within the bounds of its bucket
+	for v := int64(0); v < 1<<histogramMaxBits; v = v*9/8 + 1 {
+		i := histogramBucket(v)
+
+		if i < 0 || i >= histogramBuckets {
+			t.Fatalf("bucket of %d - got: %d, want: [0, %d)", v, i, histogramBuckets)
+		}
+
+		if histogramUpper(i) < v || (i > 0 && histogramUpper(i-1) >= v) {
+			t.Errorf("bucket of %d - got: (%d, %d], want: containing %d", v, histogramUpper(i-1), histogramUpper(i), v)
+		}
+	}
+}
+
+func TestHistogramConcurrent(t *testing.T) {
+	// Setup
+	var h Histogram
+
+	var wg sync.WaitGroup
+
+	// Test Case 1: Concurrent records are all counted
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				h.Record(time.Duration(i*1000+j) * time.Nanosecond)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if h.Count() != 8000 || h.Max() != 7999 {
+		t.Errorf("count, max - got: %d, %v, want: 8000, 7.999µs", h.Count(), h.Max())
+	}
+}
+
+func TestLatencyObserver(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	o := NewLatencyObserver()
+	c := New(WithClock(clk), WithObserver(o))
+
+	c.Set("k1", "value1")
+	c.Get("k1")
+	c.Get("k2")
+
+	// Test Case 1: The durations are recorded per operation
+	t.Run("per operation", func(t *testing.T) {
+		if got := o.Histogram(OpGet).Count(); got != 2 {
+			t.Errorf("Get count - got: %d, want: 2", got)
+		}
+
+		if got := o.Histogram(OpSet).Count(); got != 1 {
+			t.Errorf("Set count - got: %d, want: 1", got)
+		}
+
+		if got := o.Histogram(OpDelete).Count(); got != 0 {
+			t.Errorf("Delete count - got: %d, want: 0", got)
+		}
+	})
+
+	// Test Case 2: Unknown operations have no histogram
+	t.Run("unknown", func(t *testing.T) {
+		if o.Histogram("Unknown") != nil {
+			t.Errorf("Unknown histogram - got: not nil, want: nil")
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package gocache
+
+import "context"
+
+// PurgeExpired deletes all the expired entries from the cache.
+// It is mostly useful when entries are not deleted on expiry, see [WithDeleteOnExpire] and [WithCleanupInterval].
+// It returns the number of deleted entries.
+func (c *TypedCache[K, V]) PurgeExpired() int {
+	obs := c.observe(context.Background(), OpPurgeExpired, "")
+	count := c.purge()
+	obs.done(OutcomeSuccess)
+
+	return count
+}
+
+// purge is PurgeExpired, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) purge() int {
+	c.lock()
+	defer c.unlock()
+
+	count, _ := c.purgeExpired()
+
+	return count
+}
+
+// Len returns the number of entries in the cache, including the expired entries that are not deleted yet
+// and the keys cached as not found.
+func (c *TypedCache[K, V]) Len() int {
+	obs := c.observe(context.Background(), OpLen, "")
+	count := c.size()
+	obs.done(OutcomeSuccess)
+
+	return count
+}
+
+// size is Len, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) size() int {
+	c.rLock()
+	defer c.rUnlock()
+
+	return len(c.data)
+}
+
+// LiveKeys returns the list of keys, as a slice, of the entries in the cache that have not expired.
+// The keys cached as not found are not included, see [WithNegativeTtl].
+func (c *TypedCache[K, V]) LiveKeys() []K {
+	obs := c.observe(context.Background(), OpLiveKeys, "")
+	keys := c.liveKeys()
+	obs.done(OutcomeSuccess)
+
+	return keys
+}
+
+// liveKeys is LiveKeys, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) liveKeys() []K {
+	c.rLock()
+	defer c.rUnlock()
+
+	keys := make([]K, 0, len(c.data))
+	now := c.now()
+
+	for k, v := range c.data {
+		if v.found(now) {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// LiveLen returns the number of entries in the cache that have not expired, excluding the keys cached as not found.
+func (c *TypedCache[K, V]) LiveLen() int {
+	obs := c.observe(context.Background(), OpLiveLen, "")
+	count := c.liveLen()
+	obs.done(OutcomeSuccess)
+
+	return count
+}
+
+// liveLen is LiveLen, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) liveLen() int {
+	c.rLock()
+	defer c.rUnlock()
+
+	count := 0
+	now := c.now()
+
+	for _, v := range c.data {
+		if v.found(now) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// purgeExpired deletes all the expired entries from the cache, except those that can still be returned stale.
+// It returns the number of deleted entries, and whether entries that will expire remain in the cache.
+func (c *TypedCache[K, V]) purgeExpired() (int, bool) {
+	count := 0
+	pending := false
+	now := c.now().Add(-c.staleFor())
+
+	for k, v := range c.data {
+		if v.expired(now) {
+			c.remove(k, v, Expired)
+			count++
+		} else if v.ttl > 0 {
+			pending = true
+		}
+	}
+
+	return count, pending
+}
+
+// armCleanupTimer sets the cleanup timer to purge the expired entries after the cleanup interval.
+// The timer is only set when the cache has a cleanup interval and entries are not deleted on expiry,
+// and is left as is if already set.
+func (c *TypedCache[K, V]) armCleanupTimer() {
+	if c.cleanupInterval <= 0 || c.deleteOnExpire || c.cleanupArmed {
+		return
+	}
+
+	c.cleanupArmed = true
+
+	if c.cleanupTimer == nil {
+		c.cleanupTimer = c.clock.AfterFunc(c.cleanupInterval, c.onTimer(&c.cleanupDue, c.cleanup))
+	} else {
+		c.cleanupTimer.Reset(c.cleanupInterval)
+	}
+}
+
+// cleanup purges the expired entries, then sets the cleanup timer again if entries that will expire
+// remain in the cache. The timer is not set otherwise, so that an idle cache does not keep waking up.
+func (c *TypedCache[K, V]) cleanup() {
+	c.lock()
+	defer c.unlock()
+
+	c.cleanupArmed = false
+
+	if _, pending := c.purgeExpired(); pending {
+		c.armCleanupTimer()
+	}
+}
@@ -0,0 +1,123 @@
+package gocache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCachePurgeExpired(t *testing.T) {
+	// Setup
+	c := New(WithDeleteOnExpire(false), WithMaxKeys(3))
+	c.Set("k1", "value1")
+	c.SetWithTtl("k2", "value2", 50*time.Millisecond)
+	c.SetWithTtl("k3", "value3", 50*time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+
+	// Test Case 1: Expired entries remain in the store
+	t.Run("expired entries remain", func(t *testing.T) {
+		if c.Len() != 3 || len(c.Keys()) != 3 {
+			t.Errorf("Len - got: %d, want: 3", c.Len())
+		}
+
+		if c.LiveLen() != 1 {
+			t.Errorf("LiveLen - got: %d, want: 1", c.LiveLen())
+		}
+
+		if keys := c.LiveKeys(); len(keys) != 1 || keys[0] != "k1" {
+			t.Errorf("LiveKeys - got: %v, want: [k1]", keys)
+		}
+	})
+
+	// Test Case 2: Purging deletes the expired entries
+	t.Run("purge", func(t *testing.T) {
+		if count := c.PurgeExpired(); count != 2 {
+			t.Errorf("PurgeExpired - got: %d, want: 2", count)
+		}
+
+		if c.Len() != 1 {
+			t.Errorf("Len - got: %d, want: 1", c.Len())
+		}
+
+		if count := c.PurgeExpired(); count != 0 {
+			t.Errorf("PurgeExpired - got: %d, want: 0", count)
+		}
+	})
+
+	// Test Case 3: Purged entries free their slots
+	t.Run("purged slots", func(t *testing.T) {
+		if err := c.Set("k4", "value4"); err != nil {
+			t.Errorf("Set k4: err - got: %v, want: nil", err)
+		}
+	})
+}
+
+func TestCacheCleanupInterval(t *testing.T) {
+	// Setup
+	c := New(WithDeleteOnExpire(false), WithCleanupInterval(50*time.Millisecond))
+
+	// Test Case 1: Expired entries are purged periodically
+	t.Run("periodic purge", func(t *testing.T) {
+		c.Set("k1", "value1")
+		c.SetWithTtl("k2", "value2", 20*time.Millisecond)
+		c.SetWithTtl("k3", "value3", 150*time.Millisecond)
+
+		// the entries of a non-concurrent cache are purged by the next operation once the timer fired
+		time.Sleep(75 * time.Millisecond)
+
+		if keys := c.Keys(); len(keys) != 2 {
+			t.Errorf("keys length - got: %d, want: 2", len(keys))
+		}
+
+		time.Sleep(100 * time.Millisecond)
+
+		if keys := c.Keys(); len(keys) != 1 || keys[0] != "k1" {
+			t.Errorf("keys - got: %v, want: [k1]", keys)
+		}
+	})
+
+	// Test Case 2: The janitor stops when no entry will expire
+	t.Run("idle janitor", func(t *testing.T) {
+		time.Sleep(75 * time.Millisecond)
+
+		if c.cleanupArmed {
+			t.Error("cleanup armed - got: true, want: false")
+		}
+	})
+
+	// Test Case 3: The janitor restarts when an entry will expire
+	t.Run("restart janitor", func(t *testing.T) {
+		c.ChangeTtl("k1", 20*time.Millisecond)
+
+		if !c.cleanupArmed {
+			t.Error("cleanup armed - got: false, want: true")
+		}
+
+		time.Sleep(75 * time.Millisecond)
+
+		if c.Len() != 0 {
+			t.Errorf("Len - got: %d, want: 0", c.Len())
+		}
+	})
+}
+
+func TestShardedCachePurgeExpired(t *testing.T) {
+	sc := NewSharded(WithDeleteOnExpire(false))
+	sc.Set("k1", "value1")
+	sc.SetWithTtl("k2", "value2", 20*time.Millisecond)
+	sc.SetWithTtl("k3", "value3", 20*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+
+	if keys := sc.LiveKeys(); len(keys) != 1 || sc.LiveLen() != 1 {
+		t.Errorf("LiveKeys - got: %v, want: [k1]", keys)
+	}
+
+	if count := sc.PurgeExpired(); count != 2 {
+		t.Errorf("PurgeExpired - got: %d, want: 2", count)
+	}
+
+	if keys := sc.Keys(); len(keys) != 1 || sc.Len() != 1 {
+		t.Errorf("keys - got: %v, want: [k1]", keys)
+	}
+}
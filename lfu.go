@@ -0,0 +1,119 @@
+package gocache
+
+import "container/list"
+
+// lfuBucket groups the keys that have been used the same number of times.
+// Keys within a bucket are ordered by recency, the most recently used at the front.
+type lfuBucket struct {
+	freq  int
+	items *list.List
+}
+
+// lfuEntry is the position of a key in the frequency buckets.
+type lfuEntry struct {
+	bucket *list.Element
+	item   *list.Element
+}
+
+// lfuPolicy is an [EvictionPolicy] that evicts the least frequently used entry.
+// Keys are kept in buckets of the same frequency, ordered by ascending frequency, so that moving a
+// key to the next frequency and finding the least frequently used key are O(1).
+type lfuPolicy struct {
+	buckets *list.List
+	entries map[any]*lfuEntry
+}
+
+// LFU creates an [EvictionPolicy] that evicts the least frequently used entry, both reads and writes
+// count as a use of the entry.
+// Between entries with the same frequency, the least recently used one is evicted.
+func LFU() EvictionPolicy {
+	return &lfuPolicy{
+		buckets: list.New(),
+		entries: make(map[any]*lfuEntry),
+	}
+}
+
+// OnInsert adds the key with a frequency of 1.
+func (p *lfuPolicy) OnInsert(key any) {
+	if _, ok := p.entries[key]; ok {
+		p.increment(key)
+		return
+	}
+
+	front := p.buckets.Front()
+	if front == nil || front.Value.(*lfuBucket).freq != 1 {
+		front = p.buckets.PushFront(&lfuBucket{freq: 1, items: list.New()})
+	}
+
+	p.entries[key] = &lfuEntry{
+		bucket: front,
+		item:   front.Value.(*lfuBucket).items.PushFront(key),
+	}
+}
+
+// OnAccess increments the frequency of the key.
+func (p *lfuPolicy) OnAccess(key any) {
+	p.increment(key)
+}
+
+// OnUpdate increments the frequency of the key.
+func (p *lfuPolicy) OnUpdate(key any) {
+	p.increment(key)
+}
+
+// OnRemove stops tracking the key.
+func (p *lfuPolicy) OnRemove(key any) {
+	entry, ok := p.entries[key]
+
+	if !ok {
+		return
+	}
+
+	p.unlink(entry)
+	delete(p.entries, key)
+}
+
+// Victim returns the least frequently used key.
+func (p *lfuPolicy) Victim() (any, bool) {
+	front := p.buckets.Front()
+
+	if front == nil {
+		return nil, false
+	}
+
+	key := front.Value.(*lfuBucket).items.Back().Value
+	p.OnRemove(key)
+
+	return key, true
+}
+
+// increment moves the key to the bucket of the next frequency, creating it if needed.
+func (p *lfuPolicy) increment(key any) {
+	entry, ok := p.entries[key]
+
+	if !ok {
+		return
+	}
+
+	cur := entry.bucket.Value.(*lfuBucket)
+	next := entry.bucket.Next()
+
+	if next == nil || next.Value.(*lfuBucket).freq != cur.freq+1 {
+		next = p.buckets.InsertAfter(&lfuBucket{freq: cur.freq + 1, items: list.New()}, entry.bucket)
+	}
+
+	p.unlink(entry)
+
+	entry.bucket = next
+	entry.item = next.Value.(*lfuBucket).items.PushFront(key)
+}
+
+// unlink removes the key from its bucket, and removes the bucket if it has no more keys.
+func (p *lfuPolicy) unlink(entry *lfuEntry) {
+	bucket := entry.bucket.Value.(*lfuBucket)
+	bucket.items.Remove(entry.item)
+
+	if bucket.items.Len() == 0 {
+		p.buckets.Remove(entry.bucket)
+	}
+}
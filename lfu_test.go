@@ -0,0 +1,65 @@
+package gocache
+
+import "testing"
+
+func TestLFUPolicy(t *testing.T) {
+	// Setup
+	p := LFU()
+	p.OnInsert("k1")
+	p.OnInsert("k2")
+	p.OnInsert("k3")
+
+	// Test Case 1: Least frequently used is the victim
+	t.Run("least frequently used", func(t *testing.T) {
+		p.OnAccess("k1")
+		p.OnAccess("k1")
+		p.OnUpdate("k3")
+
+		if key, ok := p.Victim(); !ok || key != "k2" {
+			t.Errorf("Victim - got: %v, %v, want: k2, true", key, ok)
+		}
+	})
+
+	// Test Case 2: Least recently used among the same frequency
+	t.Run("tie on frequency", func(t *testing.T) {
+		p.OnInsert("k4")
+		p.OnAccess("k4")
+
+		// k3 and k4 both have a frequency of 2, k3 was used before k4
+		if key, ok := p.Victim(); !ok || key != "k3" {
+			t.Errorf("Victim - got: %v, %v, want: k3, true", key, ok)
+		}
+	})
+
+	// Test Case 3: Removed keys are not victims
+	t.Run("removed keys", func(t *testing.T) {
+		p.OnRemove("k4")
+
+		if key, ok := p.Victim(); !ok || key != "k1" {
+			t.Errorf("Victim - got: %v, %v, want: k1, true", key, ok)
+		}
+
+		if key, ok := p.Victim(); ok {
+			t.Errorf("Victim - got: %v, %v, want: nil, false", key, ok)
+		}
+	})
+}
+
+func TestCacheLFUEviction(t *testing.T) {
+	c := New(WithMaxKeys(2), WithEvictionPolicy(LFU))
+	c.Set("k1", "value1")
+	c.Set("k2", "value2")
+	c.Get("k1")
+	c.Get("k1")
+	c.Get("k2")
+
+	c.Set("k3", "value3")
+
+	if c.Has("k2") {
+		t.Error("Has k2 - got: true, want: false")
+	}
+
+	if !c.Has("k1") || !c.Has("k3") {
+		t.Error("Has k1, k3 - got: false, want: true")
+	}
+}
@@ -0,0 +1,295 @@
+package gocache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// flight is a call to a loader in progress, shared by all the callers loading the same key.
+type flight[V any] struct {
+	// done is closed once the loader has returned, value and err are set before.
+	done  chan struct{}
+	value V
+	err   error
+	// waiters is the number of callers waiting for the loader to return.
+	waiters int
+	// cancel cancels the context of the loader, once it has returned or all the callers gave up.
+	cancel context.CancelFunc
+}
+
+// GetOrLoad returns the value associated with the provided key from the cache, or loads it with the
+// provided loader if not found. The loaded value is set in the cache with the TTL returned by the loader,
+// which follows the semantics of SetWithTtl, and is returned even if it cannot be set in the cache.
+//
+// Concurrent calls loading the same key share a single call to the loader, whose error, if any, is
+// returned to all of them. Each caller stops waiting when its context is done, returning the context's
+// error, while the loader keeps running for the other callers. The context of the loader carries the
+// values of the context of the caller that started it, and is cancelled once all the callers gave up.
+//
+// An entry is reloaded in the background, while its current value is returned, once past the refresh
+// threshold of the cache, when it expires early, or once expired for less than the stale-while-revalidate
+// duration. An entry expired for less than the stale-if-error duration is returned if loading it fails.
+// See [WithRefreshAhead], [WithEarlyExpiration], [WithStaleWhileRevalidate] and [WithStaleIfError].
+//
+// The loader of a cache that is not safe for concurrent use, created with [New] or [NewTyped], is called
+// in the goroutine of the caller with its context instead, so that the cache is never modified from
+// another goroutine. An entry due for a reload is then reloaded before being returned, and its current
+// value is returned if loading it fails, as for an entry expired for less than the stale-while-revalidate
+// duration.
+func (c *TypedCache[K, V]) GetOrLoad(ctx context.Context, key K, loader func(ctx context.Context) (V, time.Duration, error)) (V, error) {
+	obs := c.observeKey(ctx, OpGetOrLoad, key)
+	value, hit, err := c.getOrLoad(obs.ctx, key, loader)
+
+	if hit {
+		obs.done(OutcomeHit)
+	} else if err == nil {
+		obs.done(OutcomeMiss)
+	} else {
+		obs.done(errOutcome(err))
+	}
+
+	return value, err
+}
+
+// getOrLoad is GetOrLoad, without reporting the operation to the observer of the cache.
+// It returns whether the value was returned from the cache, including stale, rather than loaded.
+func (c *TypedCache[K, V]) getOrLoad(ctx context.Context, key K, loader func(ctx context.Context) (V, time.Duration, error)) (V, bool, error) {
+	val, ok := c.getEntry(key)
+	now := c.now()
+	c.stats.read(ok && val.found(now))
+
+	if ok && val.negative && !val.expired(now) {
+		var zero V
+
+		return zero, false, ErrNegativeCached
+	}
+
+	// a non-concurrent cache cannot be reloaded in the background, its entries are reloaded below instead
+	background := c.mu != nil
+
+	if ok && !val.expired(now) {
+		reload := c.refreshDue(&val, now) || c.expiresEarly(&val, now)
+
+		if reload && background {
+			c.refresh(ctx, key, loader)
+		}
+
+		if !reload || background {
+			return val.value, true, nil
+		}
+	} else if ok && !val.negative && !val.expired(now.Add(-c.staleWhileRevalidate)) && background {
+		c.refresh(ctx, key, loader)
+
+		return val.value, true, nil
+	}
+
+	value, err := c.load(ctx, key, loader)
+
+	staleIfError := c.staleIfError
+	if !background {
+		staleIfError = c.staleFor()
+	}
+
+	if err != nil && ctx.Err() == nil && ok && !val.negative && !val.expired(now.Add(-staleIfError)) {
+		return val.value, true, nil
+	}
+
+	return value, false, err
+}
+
+// getEntry returns a copy of the entry associated with the provided key, including if it has expired.
+// The access is notified to the eviction policy and slides the expiry of the entry if it has not expired.
+func (c *TypedCache[K, V]) getEntry(key K) (cacheValue[K, V], bool) {
+	write := c.lockEntry(key)
+	defer c.unlockEntry(write)
+
+	val, ok := c.data[key]
+
+	if !ok {
+		return cacheValue[K, V]{}, false
+	}
+
+	if now := c.now(); val.found(now) {
+		c.slide(val, now)
+
+		if c.policy != nil {
+			c.policy.OnAccess(key)
+		}
+	}
+
+	return *val, true
+}
+
+// refreshDue returns whether the entry is past the refresh threshold of the cache at the provided time.
+func (c *TypedCache[K, V]) refreshDue(val *cacheValue[K, V], now time.Time) bool {
+	if c.refreshAhead == 0 || val.ttl <= 0 {
+		return false
+	}
+
+	refreshAt := val.expiryDate.Add(-val.ttl).Add(time.Duration(float64(val.ttl) * c.refreshAhead))
+
+	return !now.Before(refreshAt)
+}
+
+// refresh loads the provided key in the background, unless it is already being loaded.
+func (c *TypedCache[K, V]) refresh(ctx context.Context, key K, loader func(ctx context.Context) (V, time.Duration, error)) {
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	if _, ok := c.flights[key]; !ok {
+		c.startFlight(ctx, key, loader)
+	}
+}
+
+// load loads the provided key with the loader, or waits for the load already in progress, if any.
+// The key of a non-concurrent cache is loaded in the goroutine of the caller.
+func (c *TypedCache[K, V]) load(ctx context.Context, key K, loader func(ctx context.Context) (V, time.Duration, error)) (V, error) {
+	if c.mu == nil {
+		return c.callLoader(ctx, key, loader)
+	}
+
+	c.loadMu.Lock()
+
+	f, ok := c.flights[key]
+	if !ok {
+		// the key may have been loaded since the first lookup
+		if value, err := c.get(key); err == nil {
+			c.loadMu.Unlock()
+
+			return value, nil
+		}
+
+		f = c.startFlight(ctx, key, loader)
+	}
+
+	f.waiters++
+	c.loadMu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		c.leaveFlight(key, f)
+
+		var zero V
+
+		return zero, ctx.Err()
+	}
+}
+
+// startFlight calls the loader of the provided key in its own goroutine, then sets the loaded value in
+// the cache and wakes up the waiting callers.
+// It requires the load lock.
+func (c *TypedCache[K, V]) startFlight(ctx context.Context, key K, loader func(ctx context.Context) (V, time.Duration, error)) *flight[V] {
+	loadCtx, cancel := context.WithCancel(detachedContext{parent: ctx})
+	f := &flight[V]{done: make(chan struct{}), cancel: cancel}
+
+	if c.flights == nil {
+		c.flights = make(map[K]*flight[V])
+	}
+
+	c.flights[key] = f
+
+	go func() {
+		// the value is set before the flight ends, so that later callers find it in the cache
+		value, err := c.callLoader(loadCtx, key, loader)
+		cancel()
+		c.endFlight(key, f, value, err)
+	}()
+
+	return f
+}
+
+// callLoader calls the loader of the provided key, then sets the loaded value in the cache, or caches the
+// key as not found if the loader did not find it.
+func (c *TypedCache[K, V]) callLoader(ctx context.Context, key K, loader func(ctx context.Context) (V, time.Duration, error)) (V, error) {
+	obs := c.observeKey(ctx, OpLoad, key)
+	start := c.clock.Now()
+	value, ttl, err := loader(obs.ctx)
+	delta := c.clock.Now().Sub(start)
+	c.stats.loaded(delta, err)
+	obs.done(errOutcome(err))
+
+	if err == nil {
+		c.setLoaded(key, value, ttl, delta)
+	} else {
+		c.cacheMiss(key, err)
+	}
+
+	return value, err
+}
+
+// endFlight ends the load of the provided key with the loaded value or error, waking up the waiting callers.
+func (c *TypedCache[K, V]) endFlight(key K, f *flight[V], value V, err error) {
+	c.loadMu.Lock()
+
+	if c.flights[key] == f {
+		delete(c.flights, key)
+	}
+
+	f.value, f.err = value, err
+	c.loadMu.Unlock()
+
+	close(f.done)
+}
+
+// setLoaded sets a loaded key-value pair in the cache with a TTL, recording the time it took to load it
+// for its early expiration.
+func (c *TypedCache[K, V]) setLoaded(key K, value V, ttl time.Duration, delta time.Duration) {
+	c.set(key, value, setOptions{ttl: ttl, sliding: c.slidingTtl, maxLifetime: c.maxLifetime, delta: delta})
+}
+
+// cacheMiss caches the provided key as not found if the error of its loader wraps [ErrKeyNotFound] and
+// the cache has a negative TTL, see [WithNegativeTtl].
+func (c *TypedCache[K, V]) cacheMiss(key K, err error) {
+	if c.negativeTtl <= 0 || !errors.Is(err, ErrKeyNotFound) {
+		return
+	}
+
+	var zero V
+
+	// the entry holds no value, it is given the minimum cost rather than the estimated cost of the zero value
+	c.set(key, zero, setOptions{cost: 1, ttl: c.negativeTtl, negative: true})
+}
+
+// leaveFlight stops a caller waiting for the loader of the provided key, cancelling the loader if no
+// caller is waiting for it anymore. A later call loads the key again.
+func (c *TypedCache[K, V]) leaveFlight(key K, f *flight[V]) {
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	f.waiters--
+
+	if f.waiters > 0 {
+		return
+	}
+
+	f.cancel()
+
+	if c.flights[key] == f {
+		delete(c.flights, key)
+	}
+}
+
+// detachedContext is a context carrying the values of its parent, but not its deadline nor its cancellation,
+// so that a loader shared by several callers is not cancelled when the caller that started it gives up.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key any) any {
+	return d.parent.Value(key)
+}
@@ -0,0 +1,502 @@
+package gocache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForWaiters waits until the provided number of callers wait for the load of the key.
+func waitForWaiters(t *testing.T, c *Cache, key string, waiters int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for time.Now().Before(deadline) {
+		c.loadMu.Lock()
+		f, ok := c.flights[key]
+		n := 0
+		if ok {
+			n = f.waiters
+		}
+		c.loadMu.Unlock()
+
+		if n == waiters {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("waiters - got: fewer, want: %d", waiters)
+}
+
+func TestCacheGetOrLoad(t *testing.T) {
+	// Setup
+	c := NewSync()
+	ctx := context.Background()
+
+	// Test Case 1: Loads and sets a missing key
+	t.Run("load", func(t *testing.T) {
+		value, err := c.GetOrLoad(ctx, "k1", func(ctx context.Context) (any, time.Duration, error) {
+			return "value1", time.Minute, nil
+		})
+
+		if err != nil || value != "value1" {
+			t.Errorf("GetOrLoad k1 - got: %v, %v, want: value1, nil", value, err)
+		}
+
+		if ttl := c.GetTtl("k1"); ttl != time.Minute {
+			t.Errorf("GetTtl k1 - got: %v, want: 1m", ttl)
+		}
+	})
+
+	// Test Case 2: Existing key is not loaded
+	t.Run("hit", func(t *testing.T) {
+		value, err := c.GetOrLoad(ctx, "k1", func(ctx context.Context) (any, time.Duration, error) {
+			t.Errorf("loader - got: called, want: not called")
+
+			return nil, 0, nil
+		})
+
+		if err != nil || value != "value1" {
+			t.Errorf("GetOrLoad k1 - got: %v, %v, want: value1, nil", value, err)
+		}
+	})
+
+	// Test Case 3: Loader errors are not cached
+	t.Run("error", func(t *testing.T) {
+		errLoad := errors.New("load failed")
+
+		_, err := c.GetOrLoad(ctx, "k2", func(ctx context.Context) (any, time.Duration, error) {
+			return nil, 0, errLoad
+		})
+
+		if !errors.Is(err, errLoad) {
+			t.Errorf("GetOrLoad k2: err - got: %v, want: %v", err, errLoad)
+		}
+
+		if c.Has("k2") || len(c.flights) != 0 {
+			t.Errorf("has key k2 - got: true, want: false")
+		}
+	})
+}
+
+func TestCacheGetOrLoadCoalescing(t *testing.T) {
+	// Setup
+	c := NewSync()
+	ctx := context.Background()
+	release := make(chan struct{})
+	errLoad := errors.New("load failed")
+
+	var calls int32
+
+	loader := func(err error) func(ctx context.Context) (any, time.Duration, error) {
+		return func(ctx context.Context) (any, time.Duration, error) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+
+			return "value", 0, err
+		}
+	}
+
+	// run loads the key from all the goroutines at once, and returns the results of each of them.
+	run := func(key string, err error) ([]any, []error) {
+		values := make([]any, stressGoroutines)
+		errs := make([]error, stressGoroutines)
+
+		var wg sync.WaitGroup
+
+		for g := 0; g < stressGoroutines; g++ {
+			wg.Add(1)
+
+			go func(g int) {
+				defer wg.Done()
+
+				values[g], errs[g] = c.GetOrLoad(ctx, key, loader(err))
+			}(g)
+		}
+
+		waitForWaiters(t, c.Cache, key, stressGoroutines)
+		release <- struct{}{}
+		wg.Wait()
+
+		return values, errs
+	}
+
+	// Test Case 1: A single load for concurrent callers
+	t.Run("single load", func(t *testing.T) {
+		values, errs := run("k1", nil)
+
+		for g := range values {
+			if values[g] != "value" || errs[g] != nil {
+				t.Errorf("GetOrLoad %d - got: %v, %v, want: value, nil", g, values[g], errs[g])
+			}
+		}
+
+		if calls != 1 {
+			t.Errorf("loader calls - got: %d, want: 1", calls)
+		}
+	})
+
+	// Test Case 2: Errors are returned to all the callers
+	t.Run("shared error", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		_, errs := run("k2", errLoad)
+
+		for g, err := range errs {
+			if !errors.Is(err, errLoad) {
+				t.Errorf("GetOrLoad %d: err - got: %v, want: %v", g, err, errLoad)
+			}
+		}
+
+		if calls != 1 {
+			t.Errorf("loader calls - got: %d, want: 1", calls)
+		}
+	})
+}
+
+func TestCacheGetOrLoadCancel(t *testing.T) {
+	// Setup
+	c := NewSync()
+	release := make(chan struct{})
+	loaderDone := make(chan error, 1)
+
+	type ctxKey struct{}
+
+	loader := func(ctx context.Context) (any, time.Duration, error) {
+		if ctx.Value(ctxKey{}) != "request" {
+			t.Errorf("loader context value - got: %v, want: request", ctx.Value(ctxKey{}))
+		}
+
+		select {
+		case <-release:
+			return "value", 0, nil
+		case <-ctx.Done():
+			loaderDone <- ctx.Err()
+
+			return nil, 0, ctx.Err()
+		}
+	}
+
+	// Test Case 1: A cancelled caller stops waiting while the others keep waiting
+	t.Run("cancel one waiter", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+		ctx1, cancel1 := context.WithCancel(ctx)
+		result := make(chan any)
+
+		go func() {
+			value, _ := c.GetOrLoad(ctx, "k1", loader)
+			result <- value
+		}()
+
+		go func() {
+			waitForWaiters(t, c.Cache, "k1", 1)
+			c.GetOrLoad(ctx1, "k1", loader)
+		}()
+
+		waitForWaiters(t, c.Cache, "k1", 2)
+		cancel1()
+		waitForWaiters(t, c.Cache, "k1", 1)
+		close(release)
+
+		if value := <-result; value != "value" {
+			t.Errorf("GetOrLoad k1 - got: %v, want: value", value)
+		}
+	})
+
+	// Test Case 2: The loader is cancelled once all the callers gave up
+	t.Run("cancel all waiters", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), ctxKey{}, "request"), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := c.GetOrLoad(ctx, "k2", func(ctx context.Context) (any, time.Duration, error) {
+			<-ctx.Done()
+			loaderDone <- ctx.Err()
+
+			return nil, 0, ctx.Err()
+		})
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("GetOrLoad k2: err - got: %v, want: context.DeadlineExceeded", err)
+		}
+
+		select {
+		case err := <-loaderDone:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("loader context: err - got: %v, want: context.Canceled", err)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("loader context - got: not cancelled, want: cancelled")
+		}
+	})
+}
+
+// waitForLoads waits until no load is in progress in the cache.
+func waitForLoads(t *testing.T, c *Cache) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for time.Now().Before(deadline) {
+		c.loadMu.Lock()
+		n := len(c.flights)
+		c.loadMu.Unlock()
+
+		if n == 0 {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("loads in progress - got: some, want: none")
+}
+
+func TestSyncCacheGetOrLoadRefreshAhead(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewSync(WithClock(clk), WithRefreshAhead(0.8))
+	ctx := context.Background()
+
+	var version int32
+
+	loader := func(ctx context.Context) (any, time.Duration, error) {
+		return atomic.AddInt32(&version, 1), 10 * time.Minute, nil
+	}
+
+	c.GetOrLoad(ctx, "k1", loader)
+
+	// Test Case 1: Fresh entries are not reloaded
+	t.Run("fresh", func(t *testing.T) {
+		clk.advance(7 * time.Minute)
+
+		if value, _ := c.GetOrLoad(ctx, "k1", loader); value != int32(1) {
+			t.Errorf("GetOrLoad k1 - got: %v, want: 1", value)
+		}
+
+		waitForLoads(t, c.Cache)
+
+		if version != 1 {
+			t.Errorf("loader calls - got: %d, want: 1", version)
+		}
+	})
+
+	// Test Case 2: Entries past the threshold are reloaded in the background
+	t.Run("past threshold", func(t *testing.T) {
+		clk.advance(time.Minute)
+
+		if value, _ := c.GetOrLoad(ctx, "k1", loader); value != int32(1) {
+			t.Errorf("GetOrLoad k1 - got: %v, want: 1", value)
+		}
+
+		waitForLoads(t, c.Cache)
+
+		if value, _ := c.Get("k1"); value != int32(2) {
+			t.Errorf("Get k1 - got: %v, want: 2", value)
+		}
+
+		if ttl := c.TimeToLive("k1"); ttl != 10*time.Minute {
+			t.Errorf("TimeToLive k1 - got: %v, want: 10m", ttl)
+		}
+	})
+}
+
+func TestSyncCacheGetOrLoadStale(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewSync(WithClock(clk), WithStaleWhileRevalidate(time.Minute), WithStaleIfError(time.Hour))
+	ctx := context.Background()
+	errLoad := errors.New("load failed")
+
+	var version int32
+
+	loader := func(ctx context.Context) (any, time.Duration, error) {
+		return atomic.AddInt32(&version, 1), time.Minute, nil
+	}
+
+	failing := func(ctx context.Context) (any, time.Duration, error) {
+		return nil, 0, errLoad
+	}
+
+	c.GetOrLoad(ctx, "k1", loader)
+
+	// Test Case 1: Expired entries are served stale while revalidated
+	t.Run("stale while revalidate", func(t *testing.T) {
+		clk.advance(90 * time.Second)
+
+		if _, err := c.Get("k1"); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("Get k1: err - got: %v, want: ErrKeyNotFound", err)
+		}
+
+		if value, err := c.GetOrLoad(ctx, "k1", loader); value != int32(1) || err != nil {
+			t.Errorf("GetOrLoad k1 - got: %v, %v, want: 1, nil", value, err)
+		}
+
+		waitForLoads(t, c.Cache)
+
+		if value, _ := c.Get("k1"); value != int32(2) {
+			t.Errorf("Get k1 - got: %v, want: 2", value)
+		}
+	})
+
+	// Test Case 2: Entries expired for too long are loaded synchronously
+	t.Run("too stale", func(t *testing.T) {
+		clk.advance(3 * time.Minute)
+
+		if value, err := c.GetOrLoad(ctx, "k1", loader); value != int32(3) || err != nil {
+			t.Errorf("GetOrLoad k1 - got: %v, %v, want: 3, nil", value, err)
+		}
+	})
+
+	// Test Case 3: Expired entries are served stale when loading fails
+	t.Run("stale if error", func(t *testing.T) {
+		clk.advance(30 * time.Minute)
+
+		if value, err := c.GetOrLoad(ctx, "k1", failing); value != int32(3) || err != nil {
+			t.Errorf("GetOrLoad k1 - got: %v, %v, want: 3, nil", value, err)
+		}
+
+		clk.advance(time.Hour)
+
+		if _, err := c.GetOrLoad(ctx, "k1", failing); !errors.Is(err, errLoad) {
+			t.Errorf("GetOrLoad k1: err - got: %v, want: %v", err, errLoad)
+		}
+	})
+}
+
+func TestCacheGetOrLoadInline(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := New(WithClock(clk), WithRefreshAhead(0.8), WithStaleWhileRevalidate(time.Minute))
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	errLoad := errors.New("load failed")
+
+	var version int32
+
+	loader := func(ctx context.Context) (any, time.Duration, error) {
+		if ctx.Value(ctxKey{}) != "request" {
+			t.Errorf("loader context value - got: %v, want: request", ctx.Value(ctxKey{}))
+		}
+
+		version++
+
+		return version, 10 * time.Minute, nil
+	}
+
+	failing := func(ctx context.Context) (any, time.Duration, error) {
+		return nil, 0, errLoad
+	}
+
+	// Test Case 1: Missing keys are loaded by the caller
+	t.Run("load", func(t *testing.T) {
+		if value, err := c.GetOrLoad(ctx, "k1", loader); value != int32(1) || err != nil {
+			t.Errorf("GetOrLoad k1 - got: %v, %v, want: 1, nil", value, err)
+		}
+
+		if len(c.flights) != 0 {
+			t.Errorf("loads in progress - got: %d, want: 0", len(c.flights))
+		}
+	})
+
+	// Test Case 2: Entries past the threshold are reloaded before being returned
+	t.Run("past threshold", func(t *testing.T) {
+		clk.advance(8 * time.Minute)
+
+		if value, err := c.GetOrLoad(ctx, "k1", loader); value != int32(2) || err != nil {
+			t.Errorf("GetOrLoad k1 - got: %v, %v, want: 2, nil", value, err)
+		}
+
+		if ttl := c.TimeToLive("k1"); ttl != 10*time.Minute {
+			t.Errorf("TimeToLive k1 - got: %v, want: 10m", ttl)
+		}
+	})
+
+	// Test Case 3: Expired entries are reloaded before being returned, or returned stale if loading fails
+	t.Run("stale while revalidate", func(t *testing.T) {
+		clk.advance(10*time.Minute + 30*time.Second)
+
+		if value, err := c.GetOrLoad(ctx, "k1", failing); value != int32(2) || err != nil {
+			t.Errorf("GetOrLoad k1 - got: %v, %v, want: 2, nil", value, err)
+		}
+
+		if value, err := c.GetOrLoad(ctx, "k1", loader); value != int32(3) || err != nil {
+			t.Errorf("GetOrLoad k1 - got: %v, %v, want: 3, nil", value, err)
+		}
+	})
+}
+
+func TestSyncCacheStaleRetention(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewSync(WithClock(clk), WithStaleIfError(time.Minute))
+	c.SetWithTtl("k1", "value1", time.Minute)
+
+	// Test Case 1: Expired entries are kept for the stale duration
+	t.Run("kept", func(t *testing.T) {
+		clk.advance(90 * time.Second)
+		// the expiry timer of the stub clock never fires, it is fired by hand
+		c.expireDue()
+
+		if c.Has("k1") || c.Len() != 1 {
+			t.Errorf("Has, Len - got: %v, %d, want: false, 1", c.Has("k1"), c.Len())
+		}
+
+		if keys := c.Keys(); len(keys) != 0 {
+			t.Errorf("Keys - got: %v, want: []", keys)
+		}
+	})
+
+	// Test Case 2: Deleted once the stale duration has passed
+	t.Run("deleted", func(t *testing.T) {
+		clk.advance(time.Minute)
+		c.expireDue()
+
+		if c.Len() != 0 {
+			t.Errorf("Len - got: %d, want: 0", c.Len())
+		}
+	})
+}
+
+func TestSyncCacheStaleRetentionMaxKeys(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	rec := &removalRecorder{}
+	c := NewSync(WithClock(clk), WithMaxKeys(2), WithStaleWhileRevalidate(time.Hour), WithOnEvicted(rec.onEvicted))
+	c.SetWithTtl("a", "value1", time.Minute)
+	c.SetWithTtl("b", "value2", 2*time.Minute)
+
+	// Test Case 1: Expired entries kept for stale serving make room for new keys
+	t.Run("dropped for new keys", func(t *testing.T) {
+		clk.advance(3 * time.Minute)
+
+		if err := c.Set("c", "value3"); err != nil {
+			t.Errorf("Set c: err - got: %v, want: nil", err)
+		}
+
+		if c.Len() != 2 || c.Has("a") {
+			t.Errorf("Len, Has a - got: %d, %v, want: 2, false", c.Len(), c.Has("a"))
+		}
+
+		if got, want := rec.take(), []string{"a=value1:expired"}; !equalStrings(got, want) {
+			t.Errorf("removals - got: %v, want: %v", got, want)
+		}
+	})
+
+	// Test Case 2: Live entries are not dropped
+	t.Run("live entries kept", func(t *testing.T) {
+		c.Set("d", "value4")
+
+		if err := c.Set("e", "value5"); !errors.Is(err, ErrCacheFull) {
+			t.Errorf("Set e: err - got: %v, want: ErrCacheFull", err)
+		}
+
+		if !c.Has("c") || !c.Has("d") {
+			t.Error("has keys c and d - got: false, want: true")
+		}
+	})
+}
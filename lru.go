@@ -0,0 +1,64 @@
+package gocache
+
+import "container/list"
+
+// lruPolicy is an [EvictionPolicy] that evicts the least recently used entry.
+// Keys are kept in a list ordered by recency, the most recently used at the front, with a lookup
+// map from the key to its list element, so that all operations are O(1).
+type lruPolicy struct {
+	order    *list.List
+	elements map[any]*list.Element
+}
+
+// LRU creates an [EvictionPolicy] that evicts the least recently used entry, both reads and writes
+// count as a use of the entry.
+func LRU() EvictionPolicy {
+	return &lruPolicy{
+		order:    list.New(),
+		elements: make(map[any]*list.Element),
+	}
+}
+
+// OnInsert adds the key as the most recently used.
+func (p *lruPolicy) OnInsert(key any) {
+	if elem, ok := p.elements[key]; ok {
+		p.order.MoveToFront(elem)
+		return
+	}
+
+	p.elements[key] = p.order.PushFront(key)
+}
+
+// OnAccess marks the key as the most recently used.
+func (p *lruPolicy) OnAccess(key any) {
+	if elem, ok := p.elements[key]; ok {
+		p.order.MoveToFront(elem)
+	}
+}
+
+// OnUpdate marks the key as the most recently used.
+func (p *lruPolicy) OnUpdate(key any) {
+	p.OnInsert(key)
+}
+
+// OnRemove stops tracking the key.
+func (p *lruPolicy) OnRemove(key any) {
+	if elem, ok := p.elements[key]; ok {
+		p.order.Remove(elem)
+		delete(p.elements, key)
+	}
+}
+
+// Victim returns the least recently used key.
+func (p *lruPolicy) Victim() (any, bool) {
+	elem := p.order.Back()
+
+	if elem == nil {
+		return nil, false
+	}
+
+	p.order.Remove(elem)
+	delete(p.elements, elem.Value)
+
+	return elem.Value, true
+}
@@ -0,0 +1,186 @@
+package gocache
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestLRUPolicy(t *testing.T) {
+	// Setup
+	p := LRU()
+	p.OnInsert("k1")
+	p.OnInsert("k2")
+	p.OnInsert("k3")
+
+	// Test Case 1: Least recently inserted is the victim
+	t.Run("victim without access", func(t *testing.T) {
+		if key, ok := p.Victim(); !ok || key != "k1" {
+			t.Errorf("Victim - got: %v, %v, want: k1, true", key, ok)
+		}
+	})
+
+	// Test Case 2: Access and update move keys to the front
+	t.Run("victim after access", func(t *testing.T) {
+		p.OnAccess("k2")
+		p.OnInsert("k4")
+		p.OnUpdate("k3")
+
+		if key, ok := p.Victim(); !ok || key != "k2" {
+			t.Errorf("Victim - got: %v, %v, want: k2, true", key, ok)
+		}
+	})
+
+	// Test Case 3: Removed keys are not victims
+	t.Run("victim after remove", func(t *testing.T) {
+		p.OnRemove("k4")
+
+		if key, ok := p.Victim(); !ok || key != "k3" {
+			t.Errorf("Victim - got: %v, %v, want: k3, true", key, ok)
+		}
+
+		if key, ok := p.Victim(); ok {
+			t.Errorf("Victim - got: %v, %v, want: nil, false", key, ok)
+		}
+	})
+}
+
+func TestCacheLRUEviction(t *testing.T) {
+	// Setup
+	c := New(WithMaxKeys(3), WithEvictionPolicy(LRU))
+	c.Set("k1", "value1")
+	c.Set("k2", "value2")
+	c.Set("k3", "value3")
+
+	// Test Case 1: Least recently used key is evicted
+	t.Run("evicts least recently used", func(t *testing.T) {
+		c.Get("k1")
+
+		if err := c.Set("k4", "value4"); err != nil {
+			t.Errorf("Set k4: err - got: %v, want: nil", err)
+		}
+
+		if c.Has("k2") {
+			t.Error("Has k2 - got: true, want: false")
+		}
+
+		if len(c.data) != 3 {
+			t.Errorf("cache data length - got: %d, want: 3", len(c.data))
+		}
+	})
+
+	// Test Case 2: Updating a key counts as a use
+	t.Run("update counts as use", func(t *testing.T) {
+		c.Set("k3", "new value3")
+		c.Set("k5", "value5")
+
+		if c.Has("k1") {
+			t.Error("Has k1 - got: true, want: false")
+		}
+
+		if value, _ := c.Get("k3"); value != "new value3" {
+			t.Errorf("Get k3 - got: %v, want: new value3", value)
+		}
+	})
+
+	// Test Case 3: Deleted keys free a slot without eviction
+	t.Run("delete frees slot", func(t *testing.T) {
+		c.Delete("k4")
+		c.Set("k6", "value6")
+
+		for _, key := range []string{"k3", "k5", "k6"} {
+			if !c.Has(key) {
+				t.Errorf("Has %s - got: false, want: true", key)
+			}
+		}
+	})
+
+	// Test Case 4: Cleared cache evicts from scratch
+	t.Run("clear", func(t *testing.T) {
+		c.Clear()
+
+		for i := 0; i < 5; i++ {
+			c.Set(fmt.Sprintf("k%d", i), i)
+		}
+
+		if keys := c.Keys(); len(keys) != 3 {
+			t.Errorf("keys length - got: %d, want: 3", len(keys))
+		}
+
+		if !c.Has("k2") || c.Has("k1") {
+			t.Error("Has k2, k1 - want: true, false")
+		}
+	})
+}
+
+func TestCacheNoEviction(t *testing.T) {
+	c := New(WithMaxKeys(1), WithEvictionPolicy(NoEviction))
+	c.Set("k1", "value1")
+
+	if err := c.Set("k2", "value2"); !errors.Is(err, ErrCacheFull) {
+		t.Errorf("Set k2: err - got: %v, want: ErrCacheFull", err)
+	}
+
+	if !c.Has("k1") {
+		t.Error("Has k1 - got: false, want: true")
+	}
+}
+
+func TestShardedCacheLRUEviction(t *testing.T) {
+	sc := NewSharded(WithShards(4), WithMaxKeys(8), WithEvictionPolicy(LRU))
+
+	for i := 0; i < 100; i++ {
+		if err := sc.Set(strconv.Itoa(i), i); err != nil {
+			t.Errorf("Set %d: err - got: %v, want: nil", i, err)
+		}
+	}
+
+	if sc.Len() != 8 {
+		t.Errorf("Len - got: %d, want: 8", sc.Len())
+	}
+
+	if !sc.Has("99") {
+		t.Error("Has 99 - got: false, want: true")
+	}
+}
+
+func TestShardedCacheLRUEvictionMoreShardsThanKeys(t *testing.T) {
+	sc := NewSharded(WithShards(16), WithMaxKeys(4), WithEvictionPolicy(LRU))
+
+	for i := 0; i < 100; i++ {
+		if err := sc.Set(strconv.Itoa(i), i); err != nil {
+			t.Errorf("Set %d: err - got: %v, want: nil", i, err)
+		}
+	}
+
+	// each of the 16 shards holds up to ceil(4/16) = 1 key
+	for i, c := range sc.shards {
+		if c.Len() > 1 {
+			t.Errorf("Len of shard %d - got: %d, want: at most 1", i, c.Len())
+		}
+	}
+
+	if sc.Len() > 16 {
+		t.Errorf("Len - got: %d, want: at most 16", sc.Len())
+	}
+
+	if !sc.Has("99") {
+		t.Error("Has 99 - got: false, want: true")
+	}
+}
+
+func BenchmarkCacheSetLRU(b *testing.B) {
+	c := New(WithMaxKeys(keyPoolSize/2), WithEvictionPolicy(LRU))
+
+	keys := make([]string, keyPoolSize)
+	for i := 0; i < keyPoolSize; i++ {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Set(keys[i%keyPoolSize], i)
+	}
+}
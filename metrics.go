@@ -0,0 +1,120 @@
+package gocache
+
+import (
+	"bytes"
+	"expvar"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultName is the default name of a cache in its metrics.
+const defaultName = "default"
+
+// StatsSource is a named cache whose statistics are exported by [MetricsHandler].
+// It is implemented by all the caches of the package.
+type StatsSource interface {
+	// Name returns the name of the cache, used as the name label of its metrics.
+	Name() string
+	// Stats returns a snapshot of the statistics of the cache.
+	Stats() Stats
+}
+
+// Name returns the name of the cache, see [WithName].
+func (c *TypedCache[K, V]) Name() string {
+	return c.name
+}
+
+// metric is a metric exported by [MetricsHandler], with a value derived from the statistics of a cache.
+type metric struct {
+	name string
+	// help and typ describe the metric, they are empty for a metric continuing the family of the
+	// previous one with another label.
+	help string
+	typ  string
+	// label is an additional label of the metric, besides the name of the cache.
+	label string
+	value func(s Stats) string
+}
+
+// metrics are the metrics exported by [MetricsHandler], in the order they are rendered.
+var metrics = []metric{
+	{"gocache_hits_total", "Number of reads that found a value.", "counter", "", func(s Stats) string { return formatInt(s.Hits) }},
+	{"gocache_misses_total", "Number of reads that did not find a value.", "counter", "", func(s Stats) string { return formatInt(s.Misses) }},
+	{"gocache_sets_total", "Number of values set, including the loaded values.", "counter", "", func(s Stats) string { return formatInt(s.Sets) }},
+	{"gocache_deletes_total", "Number of entries deleted.", "counter", "", func(s Stats) string { return formatInt(s.Deletes) }},
+	{"gocache_expirations_total", "Number of expired entries removed.", "counter", "", func(s Stats) string { return formatInt(s.Expirations) }},
+	{"gocache_evictions_total", "Number of entries evicted.", "counter", "", func(s Stats) string { return formatInt(s.Evictions) }},
+	{"gocache_keys", "Number of entries in the cache.", "gauge", "", func(s Stats) string { return formatInt(int64(s.Keys)) }},
+	{"gocache_loads_total", "Number of calls to a loader.", "counter", `result="success"`, func(s Stats) string { return formatInt(s.LoadSuccesses) }},
+	{"gocache_loads_total", "", "", `result="failure"`, func(s Stats) string { return formatInt(s.LoadFailures) }},
+	{"gocache_load_duration_seconds_total", "Total time spent in the calls to the loaders.", "counter", "", func(s Stats) string {
+		return strconv.FormatFloat(s.LoadTime.Seconds(), 'g', -1, 64)
+	}},
+}
+
+// metricsHandler renders the statistics of caches in the Prometheus text exposition format.
+type metricsHandler struct {
+	caches []StatsSource
+}
+
+// MetricsHandler returns an [http.Handler] that renders the statistics of the provided caches in the
+// Prometheus text exposition format, for a Prometheus server to scrape. The metrics of each cache carry
+// its name as the name label, the caches should therefore have distinct names, see [WithName].
+func MetricsHandler(caches ...StatsSource) http.Handler {
+	return &metricsHandler{caches: caches}
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	stats := make([]Stats, len(h.caches))
+	for i, c := range h.caches {
+		stats[i] = c.Stats()
+	}
+
+	var buf bytes.Buffer
+
+	for _, m := range metrics {
+		if m.help != "" {
+			buf.WriteString("# HELP " + m.name + " " + m.help + "\n")
+			buf.WriteString("# TYPE " + m.name + " " + m.typ + "\n")
+		}
+
+		for i, c := range h.caches {
+			buf.WriteString(m.name + `{name="` + labelEscaper.Replace(c.Name()) + `"`)
+
+			if m.label != "" {
+				buf.WriteString("," + m.label)
+			}
+
+			buf.WriteString("} " + m.value(stats[i]) + "\n")
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// labelEscaper escapes the values of the labels in the Prometheus text exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatInt formats an integer metric value.
+func formatInt(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+var (
+	// expvarCaches is the expvar map the caches are published in, created on the first publication.
+	expvarCaches     *expvar.Map
+	expvarCachesOnce sync.Once
+)
+
+// publishExpvar publishes the statistics returned by the provided function under expvar, in the
+// "gocache" map under the provided name.
+func publishExpvar(name string, stats func() Stats) {
+	expvarCachesOnce.Do(func() {
+		expvarCaches = expvar.NewMap("gocache")
+	})
+
+	expvarCaches.Set(name, expvar.Func(func() any { return stats() }))
+}
@@ -0,0 +1,107 @@
+package gocache
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	// Setup
+	users := NewSync(WithName("users"))
+	sessions := NewSharded(WithName("sessions"), WithShards(4))
+
+	users.Set("k1", "value1")
+	users.Get("k1")
+	users.Get("k2")
+	sessions.Set("k1", "value1")
+	sessions.Set("k2", "value2")
+
+	rec := httptest.NewRecorder()
+	MetricsHandler(users, sessions).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	// Test Case 1: The content type is the text exposition format
+	t.Run("content type", func(t *testing.T) {
+		if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+			t.Errorf("Content-Type - got: %q, want: text/plain; version=0.0.4", got)
+		}
+	})
+
+	// Test Case 2: The metrics of each cache carry its name
+	t.Run("metrics", func(t *testing.T) {
+		for _, line := range []string{
+			"# TYPE gocache_hits_total counter",
+			`gocache_hits_total{name="users"} 1`,
+			`gocache_misses_total{name="users"} 1`,
+			`gocache_hits_total{name="sessions"} 0`,
+			"# TYPE gocache_keys gauge",
+			`gocache_keys{name="users"} 1`,
+			`gocache_keys{name="sessions"} 2`,
+			`gocache_loads_total{name="users",result="failure"} 0`,
+			`gocache_load_duration_seconds_total{name="sessions"} 0`,
+		} {
+			if !strings.Contains(body, line+"\n") {
+				t.Errorf("metrics - got: %q, want line: %q", body, line)
+			}
+		}
+	})
+
+	// Test Case 3: Each metric family is described once
+	t.Run("families", func(t *testing.T) {
+		if got := strings.Count(body, "# TYPE gocache_loads_total "); got != 1 {
+			t.Errorf("TYPE gocache_loads_total count - got: %d, want: 1", got)
+		}
+	})
+
+	// Test Case 4: The names are escaped
+	t.Run("escaped", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		MetricsHandler(New(WithName("a\"b\\c"))).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+		if line := `gocache_keys{name="a\"b\\c"} 0`; !strings.Contains(rec.Body.String(), line) {
+			t.Errorf("metrics - got: %q, want line: %q", rec.Body.String(), line)
+		}
+	})
+}
+
+func TestExpvar(t *testing.T) {
+	// Setup
+	c := NewSync(WithName("expvar-cache"), WithExpvar(true))
+	sc := NewSharded(WithName("expvar-sharded"), WithExpvar(true))
+
+	c.Set("k1", "value1")
+	c.Get("k1")
+	sc.Set("k1", "value1")
+
+	published := func(name string) Stats {
+		var stats Stats
+
+		m, ok := expvar.Get("gocache").(*expvar.Map)
+		if !ok || m.Get(name) == nil {
+			t.Fatalf("expvar %s - got: nil, want: published", name)
+		}
+
+		if err := json.Unmarshal([]byte(m.Get(name).String()), &stats); err != nil {
+			t.Fatalf("expvar %s - got: %v, want: Stats", name, err)
+		}
+
+		return stats
+	}
+
+	// Test Case 1: The statistics of a cache are published under its name
+	t.Run("cache", func(t *testing.T) {
+		if stats := published("expvar-cache"); stats.Hits != 1 || stats.Keys != 1 {
+			t.Errorf("hits, keys - got: %d, %d, want: 1, 1", stats.Hits, stats.Keys)
+		}
+	})
+
+	// Test Case 2: A sharded cache is published as a whole
+	t.Run("sharded", func(t *testing.T) {
+		if stats := published("expvar-sharded"); stats.Sets != 1 || stats.Keys != 1 {
+			t.Errorf("sets, keys - got: %d, %d, want: 1, 1", stats.Sets, stats.Keys)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package gocache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheNegativeTtl(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	rec := &removalRecorder{}
+	c := NewSync(WithClock(clk), WithNegativeTtl(time.Minute), WithOnEvicted(rec.onEvicted))
+	ctx := context.Background()
+
+	var calls int32
+
+	notFound := func(ctx context.Context) (any, time.Duration, error) {
+		atomic.AddInt32(&calls, 1)
+
+		return nil, 0, fmt.Errorf("user 42: %w", ErrKeyNotFound)
+	}
+
+	// Test Case 1: Keys not found by the loader are cached
+	t.Run("cached", func(t *testing.T) {
+		if _, err := c.GetOrLoad(ctx, "k1", notFound); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("GetOrLoad k1: err - got: %v, want: ErrKeyNotFound", err)
+		}
+
+		if _, err := c.GetOrLoad(ctx, "k1", notFound); !errors.Is(err, ErrNegativeCached) {
+			t.Errorf("GetOrLoad k1: err - got: %v, want: ErrNegativeCached", err)
+		}
+
+		if calls != 1 {
+			t.Errorf("loader calls - got: %d, want: 1", calls)
+		}
+	})
+
+	// Test Case 2: Negative entries are not values
+	t.Run("not a value", func(t *testing.T) {
+		_, err := c.Get("k1")
+
+		if !errors.Is(err, ErrNegativeCached) || !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("Get k1: err - got: %v, want: ErrNegativeCached", err)
+		}
+
+		if c.Has("k1") || len(c.Keys()) != 0 || len(c.LiveKeys()) != 0 || c.GetTtl("k1") != -1 {
+			t.Errorf("has key k1 - got: true, want: false")
+		}
+
+		if c.Len() != 1 {
+			t.Errorf("Len - got: %d, want: 1", c.Len())
+		}
+	})
+
+	// Test Case 3: Negative entries expire with the negative TTL
+	t.Run("expired", func(t *testing.T) {
+		clk.advance(time.Minute + time.Second)
+
+		if _, err := c.GetOrLoad(ctx, "k1", notFound); errors.Is(err, ErrNegativeCached) {
+			t.Errorf("GetOrLoad k1: err - got: ErrNegativeCached, want: ErrKeyNotFound")
+		}
+
+		if calls != 2 {
+			t.Errorf("loader calls - got: %d, want: 2", calls)
+		}
+	})
+
+	// Test Case 4: Setting a value replaces the negative entry
+	t.Run("replaced", func(t *testing.T) {
+		c.Set("k1", "value1")
+
+		if value, err := c.Get("k1"); err != nil || value != "value1" {
+			t.Errorf("Get k1 - got: %v, %v, want: value1, nil", value, err)
+		}
+
+		if removals := rec.take(); len(removals) != 0 {
+			t.Errorf("removals - got: %v, want: []", removals)
+		}
+	})
+
+	// Test Case 5: Other loader errors are not cached
+	t.Run("other errors", func(t *testing.T) {
+		c.GetOrLoad(ctx, "k2", func(ctx context.Context) (any, time.Duration, error) {
+			return nil, 0, errors.New("load failed")
+		})
+
+		if c.Len() != 1 {
+			t.Errorf("Len - got: %d, want: 1", c.Len())
+		}
+	})
+}
+
+func TestCacheNegativeTtlMaxKeys(t *testing.T) {
+	// Setup
+	c := NewSync(WithMaxKeys(2), WithNegativeTtl(time.Minute))
+	ctx := context.Background()
+
+	notFound := func(ctx context.Context) (any, time.Duration, error) {
+		return nil, 0, ErrKeyNotFound
+	}
+
+	c.GetOrLoad(ctx, "missing1", notFound)
+	c.GetOrLoad(ctx, "missing2", notFound)
+
+	// Test Case 1: Negative entries make room for the values
+	t.Run("values not rejected", func(t *testing.T) {
+		if err := c.Set("real", 1); err != nil {
+			t.Errorf("Set real: err - got: %v, want: nil", err)
+		}
+
+		if value, err := c.Get("real"); err != nil || value != 1 {
+			t.Errorf("Get real - got: %v, %v, want: 1, nil", value, err)
+		}
+
+		if c.Len() != 2 {
+			t.Errorf("Len - got: %d, want: 2", c.Len())
+		}
+	})
+
+	// Test Case 2: Values are not dropped for negative entries
+	t.Run("values kept", func(t *testing.T) {
+		c.Set("real2", 2)
+
+		if err := c.Set("real3", 3); !errors.Is(err, ErrCacheFull) {
+			t.Errorf("Set real3: err - got: %v, want: ErrCacheFull", err)
+		}
+
+		if !c.Has("real") || !c.Has("real2") {
+			t.Error("has keys real and real2 - got: false, want: true")
+		}
+	})
+}
+
+func TestCacheNegativeTtlBatch(t *testing.T) {
+	// Setup
+	c := NewSync(WithNegativeTtl(time.Minute))
+	rec := &batchRecorder{missing: map[string]bool{"k2": true}}
+
+	c.GetManyOrLoad(context.Background(), []string{"k1", "k2"}, rec.load)
+
+	// Test Case 1: Keys missing from the batch are cached
+	t.Run("cached", func(t *testing.T) {
+		values, err := c.GetManyOrLoad(context.Background(), []string{"k1", "k2"}, rec.load)
+
+		var batchErr *BatchError[string]
+		if !errors.As(err, &batchErr) || !errors.Is(batchErr.Errors["k2"], ErrNegativeCached) {
+			t.Errorf("GetManyOrLoad: err - got: %v, want: k2: ErrNegativeCached", err)
+		}
+
+		if len(values) != 1 || rec.callCount() != 1 {
+			t.Errorf("values, calls - got: %v, %v, want: map[k1:v-k1], 1 call", values, rec.calls)
+		}
+	})
+
+	// Test Case 2: Not cached without a negative TTL
+	t.Run("disabled", func(t *testing.T) {
+		c := NewSync()
+		c.GetManyOrLoad(context.Background(), []string{"k2"}, rec.load)
+
+		if c.Len() != 0 {
+			t.Errorf("Len - got: %d, want: 0", c.Len())
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package gocache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Observer is notified before and after each operation on a cache, to measure its latency or trace it.
+// It is set with [WithObserver], and must be safe for concurrent use when the cache is.
+// The methods on the statistics, the subscriptions and the name of the cache are not reported.
+type Observer interface {
+	// Before is called before an operation on the provided key, the key is empty for the operations on
+	// the whole cache. The context is that of the caller for the loading methods, and of the loader for
+	// the loads, it is [context.Background] otherwise. The returned context is passed to After, and to the
+	// loader for the loading methods, so that it can carry a span.
+	Before(ctx context.Context, op Op, key string) context.Context
+	// After is called once the operation is done, with the context returned by Before, the outcome of
+	// the operation and its duration.
+	After(ctx context.Context, op Op, key string, outcome Outcome, d time.Duration)
+}
+
+// Op is an operation on a cache reported to its [Observer].
+type Op string
+
+const (
+	// OpSet is a call to Set, SetWithTtl, SetWithCost, SetWithSlidingTtl or SetWithExpiry.
+	OpSet Op = "Set"
+	// OpGet is a call to Get.
+	OpGet Op = "Get"
+	// OpGetOrLoad is a call to GetOrLoad.
+	OpGetOrLoad Op = "GetOrLoad"
+	// OpGetManyOrLoad is a call to GetManyOrLoad.
+	OpGetManyOrLoad Op = "GetManyOrLoad"
+	// OpLoad is a call to the loader of GetOrLoad.
+	OpLoad Op = "Load"
+	// OpBatchLoad is a call to the batch loader of GetManyOrLoad.
+	OpBatchLoad Op = "BatchLoad"
+	// OpGetAndDelete is a call to GetAndDelete.
+	OpGetAndDelete Op = "GetAndDelete"
+	// OpDelete is a call to Delete.
+	OpDelete Op = "Delete"
+	// OpHas is a call to Has.
+	OpHas Op = "Has"
+	// OpChangeTtl is a call to ChangeTtl.
+	OpChangeTtl Op = "ChangeTtl"
+	// OpGetTtl is a call to GetTtl.
+	OpGetTtl Op = "GetTtl"
+	// OpExpireAt is a call to ExpireAt.
+	OpExpireAt Op = "ExpireAt"
+	// OpPersist is a call to Persist.
+	OpPersist Op = "Persist"
+	// OpTouch is a call to Touch.
+	OpTouch Op = "Touch"
+	// OpTimeToLive is a call to TimeToLive.
+	OpTimeToLive Op = "TimeToLive"
+	// OpClear is a call to Clear.
+	OpClear Op = "Clear"
+	// OpPurgeExpired is a call to PurgeExpired.
+	OpPurgeExpired Op = "PurgeExpired"
+	// OpKeys is a call to Keys.
+	OpKeys Op = "Keys"
+	// OpLen is a call to Len.
+	OpLen Op = "Len"
+	// OpLiveKeys is a call to LiveKeys.
+	OpLiveKeys Op = "LiveKeys"
+	// OpLiveLen is a call to LiveLen.
+	OpLiveLen Op = "LiveLen"
+	// OpCost is a call to Cost.
+	OpCost Op = "Cost"
+	// OpSaveTo is a call to SaveTo.
+	OpSaveTo Op = "SaveTo"
+	// OpLoadFrom is a call to LoadFrom.
+	OpLoadFrom Op = "LoadFrom"
+)
+
+// ops are all the operations reported to the observers.
+var ops = []Op{
+	OpSet, OpGet, OpGetOrLoad, OpGetManyOrLoad, OpLoad, OpBatchLoad, OpGetAndDelete, OpDelete, OpHas,
+	OpChangeTtl, OpGetTtl, OpExpireAt, OpPersist, OpTouch, OpTimeToLive, OpClear, OpPurgeExpired, OpKeys,
+	OpLen, OpLiveKeys, OpLiveLen, OpCost, OpSaveTo, OpLoadFrom,
+}
+
+// Outcome is the outcome of an operation reported to an [Observer].
+type Outcome int
+
+const (
+	// OutcomeHit means the key was found, or the value was returned from the cache by a loading method.
+	OutcomeHit Outcome = iota
+	// OutcomeMiss means the key was not found, or the value was loaded by a loading method.
+	OutcomeMiss
+	// OutcomeSuccess means the operation succeeded, for the operations that do not look up a key.
+	OutcomeSuccess
+	// OutcomeFailure means the operation returned an error.
+	OutcomeFailure
+)
+
+// String returns the name of the outcome.
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeHit:
+		return "hit"
+	case OutcomeMiss:
+		return "miss"
+	case OutcomeSuccess:
+		return "success"
+	case OutcomeFailure:
+		return "failure"
+	default:
+		return fmt.Sprintf("Outcome(%d)", int(o))
+	}
+}
+
+// foundOutcome returns the outcome of an operation that found the key or not.
+func foundOutcome(found bool) Outcome {
+	if found {
+		return OutcomeHit
+	}
+
+	return OutcomeMiss
+}
+
+// errOutcome returns the outcome of an operation that returned the provided error, a key not found
+// being a miss rather than a failure.
+func errOutcome(err error) Outcome {
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.Is(err, ErrKeyNotFound):
+		return OutcomeMiss
+	default:
+		return OutcomeFailure
+	}
+}
+
+// observation is an operation in progress reported to the observer of a cache.
+// It is empty when the cache has no observer.
+type observation struct {
+	observer Observer
+	clock    Clock
+	// ctx is the context returned by the observer, or the context of the operation if there is no observer.
+	ctx   context.Context
+	op    Op
+	key   string
+	start time.Time
+}
+
+// observe reports the start of an operation on the provided key to the observer of the cache, if any.
+func (c *TypedCache[K, V]) observe(ctx context.Context, op Op, key string) observation {
+	if c.observer == nil {
+		return observation{ctx: ctx}
+	}
+
+	ctx = c.observer.Before(ctx, op, key)
+
+	return observation{observer: c.observer, clock: c.clock, ctx: ctx, op: op, key: key, start: c.clock.Now()}
+}
+
+// observeKey reports the start of an operation on the provided key to the observer of the cache, if any.
+// The key is only formatted if the cache has an observer.
+func (c *TypedCache[K, V]) observeKey(ctx context.Context, op Op, key K) observation {
+	if c.observer == nil {
+		return observation{ctx: ctx}
+	}
+
+	return c.observe(ctx, op, keyString(key))
+}
+
+// done reports the end of the operation with the provided outcome.
+func (o observation) done(outcome Outcome) {
+	if o.observer != nil {
+		o.observer.After(o.ctx, o.op, o.key, outcome, o.clock.Now().Sub(o.start))
+	}
+}
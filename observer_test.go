@@ -0,0 +1,223 @@
+package gocache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// observerRecorder records the operations reported to an observer.
+type observerRecorder struct {
+	mu     sync.Mutex
+	before []string
+	after  []observed
+}
+
+// observed is an operation reported to an observer once done.
+type observed struct {
+	op      Op
+	key     string
+	outcome Outcome
+	d       time.Duration
+	span    any
+}
+
+// spanKey is the context key of the span set by the recorder.
+type spanKey struct{}
+
+func (r *observerRecorder) Before(ctx context.Context, op Op, key string) context.Context {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.before = append(r.before, string(op)+" "+key)
+
+	return context.WithValue(ctx, spanKey{}, string(op))
+}
+
+func (r *observerRecorder) After(ctx context.Context, op Op, key string, outcome Outcome, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.after = append(r.after, observed{op: op, key: key, outcome: outcome, d: d, span: ctx.Value(spanKey{})})
+}
+
+// take returns the operations reported once done so far and forgets them.
+func (r *observerRecorder) take() []observed {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	after := r.after
+	r.before, r.after = nil, nil
+
+	return after
+}
+
+func TestCacheObserver(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	rec := &observerRecorder{}
+	c := NewSync(WithClock(clk), WithObserver(rec))
+	ctx := context.Background()
+
+	// Test Case 1: Each operation is reported once with its outcome
+	t.Run("outcomes", func(t *testing.T) {
+		c.Set("k1", "value1")
+		c.SetWithTtl("k2", "value2", time.Minute)
+		c.Get("k1")
+		c.Get("k3")
+		c.Has("k2")
+		c.Delete("k3")
+		c.Touch("k2")
+		c.TimeToLive("k1")
+		c.GetAndDelete("k2")
+		c.Clear()
+
+		want := []observed{
+			{op: OpSet, key: "k1", outcome: OutcomeSuccess, span: "Set"},
+			{op: OpSet, key: "k2", outcome: OutcomeSuccess, span: "Set"},
+			{op: OpGet, key: "k1", outcome: OutcomeHit, span: "Get"},
+			{op: OpGet, key: "k3", outcome: OutcomeMiss, span: "Get"},
+			{op: OpHas, key: "k2", outcome: OutcomeHit, span: "Has"},
+			{op: OpDelete, key: "k3", outcome: OutcomeMiss, span: "Delete"},
+			{op: OpTouch, key: "k2", outcome: OutcomeHit, span: "Touch"},
+			{op: OpTimeToLive, key: "k1", outcome: OutcomeHit, span: "TimeToLive"},
+			{op: OpGetAndDelete, key: "k2", outcome: OutcomeHit, span: "GetAndDelete"},
+			{op: OpClear, key: "", outcome: OutcomeSuccess, span: "Clear"},
+		}
+
+		if got := rec.take(); !reflect.DeepEqual(got, want) {
+			t.Errorf("observed - got: %+v, want: %+v", got, want)
+		}
+	})
+
+	// Test Case 2: Failed operations are reported as failures
+	t.Run("failure", func(t *testing.T) {
+		c := New(WithMaxKeys(1), WithObserver(rec))
+		c.Set("k1", "value1")
+		c.Set("k2", "value2")
+
+		if got := rec.take(); len(got) != 2 || got[1].outcome != OutcomeFailure {
+			t.Errorf("observed - got: %+v, want: Set k2 failure", got)
+		}
+	})
+
+	// Test Case 3: The loads are reported with the context returned by the observer
+	t.Run("loads", func(t *testing.T) {
+		var span any
+
+		loader := func(ctx context.Context) (any, time.Duration, error) {
+			span = ctx.Value(spanKey{})
+			clk.advance(time.Second)
+
+			return "value1", 0, nil
+		}
+
+		c.GetOrLoad(ctx, "k1", loader)
+		c.GetOrLoad(ctx, "k1", loader)
+		c.GetOrLoad(ctx, "k2", func(ctx context.Context) (any, time.Duration, error) {
+			return nil, 0, errors.New("load failed")
+		})
+
+		if span != "Load" {
+			t.Errorf("loader span - got: %v, want: Load", span)
+		}
+
+		got := rec.take()
+
+		want := []observed{
+			{op: OpLoad, key: "k1", outcome: OutcomeSuccess, d: time.Second, span: "Load"},
+			{op: OpGetOrLoad, key: "k1", outcome: OutcomeMiss, d: time.Second, span: "GetOrLoad"},
+			{op: OpGetOrLoad, key: "k1", outcome: OutcomeHit, span: "GetOrLoad"},
+			{op: OpLoad, key: "k2", outcome: OutcomeFailure, span: "Load"},
+			{op: OpGetOrLoad, key: "k2", outcome: OutcomeFailure, span: "GetOrLoad"},
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("observed - got: %+v, want: %+v", got, want)
+		}
+	})
+
+	// Test Case 4: Batch loads are reported once
+	t.Run("batch loads", func(t *testing.T) {
+		c.GetManyOrLoad(ctx, []string{"k1", "k3", "k4"}, func(ctx context.Context, missing []string) (map[string]any, error) {
+			return map[string]any{"k3": "value3", "k4": "value4"}, nil
+		})
+
+		want := []observed{
+			{op: OpBatchLoad, key: "", outcome: OutcomeSuccess, span: "BatchLoad"},
+			{op: OpGetManyOrLoad, key: "", outcome: OutcomeSuccess, span: "GetManyOrLoad"},
+		}
+
+		if got := rec.take(); !reflect.DeepEqual(got, want) {
+			t.Errorf("observed - got: %+v, want: %+v", got, want)
+		}
+	})
+
+	// Test Case 5: The operations on the whole cache are reported
+	t.Run("whole cache", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		c.Keys()
+		c.Len()
+		c.LiveKeys()
+		c.LiveLen()
+		c.Cost()
+		c.SaveTo(&buf)
+		c.LoadFrom(&buf)
+		c.LoadFrom(strings.NewReader("not a snapshot"))
+		c.Stats()
+
+		want := []observed{
+			{op: OpKeys, key: "", outcome: OutcomeSuccess, span: "Keys"},
+			{op: OpLen, key: "", outcome: OutcomeSuccess, span: "Len"},
+			{op: OpLiveKeys, key: "", outcome: OutcomeSuccess, span: "LiveKeys"},
+			{op: OpLiveLen, key: "", outcome: OutcomeSuccess, span: "LiveLen"},
+			{op: OpCost, key: "", outcome: OutcomeSuccess, span: "Cost"},
+			{op: OpSaveTo, key: "", outcome: OutcomeSuccess, span: "SaveTo"},
+			{op: OpLoadFrom, key: "", outcome: OutcomeSuccess, span: "LoadFrom"},
+			{op: OpLoadFrom, key: "", outcome: OutcomeFailure, span: "LoadFrom"},
+		}
+
+		if got := rec.take(); !reflect.DeepEqual(got, want) {
+			t.Errorf("observed - got: %+v, want: %+v", got, want)
+		}
+	})
+}
+
+func TestShardedCacheObserver(t *testing.T) {
+	// Setup
+	rec := &observerRecorder{}
+	sc := NewSharded(WithShards(4), WithObserver(rec))
+
+	var buf bytes.Buffer
+
+	sc.Set("k1", "value1")
+	sc.Get("k1")
+	sc.Keys()
+	sc.LiveKeys()
+	sc.Len()
+	sc.LiveLen()
+	sc.Cost()
+	sc.SaveTo(&buf)
+	sc.LoadFrom(&buf)
+	sc.Stats()
+	sc.PurgeExpired()
+	sc.Clear()
+
+	// Test Case 1: The operations on the whole cache are reported once
+	want := []Op{OpSet, OpGet, OpKeys, OpLiveKeys, OpLen, OpLiveLen, OpCost, OpSaveTo, OpLoadFrom, OpPurgeExpired, OpClear}
+
+	got := make([]Op, 0, len(want))
+	for _, o := range rec.take() {
+		got = append(got, o.op)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("observed - got: %v, want: %v", got, want)
+	}
+}
@@ -2,14 +2,114 @@ package gocache
 
 import "time"
 
-// OptFunc defines a function type for configuring a [Cache] instance.
-type OptFunc func(*Cache)
+// OptFunc defines a function type for configuring a cache instance.
+// The same options apply to a [Cache], a [TypedCache], a [SyncCache] and a [ShardedCache].
+type OptFunc func(*config)
+
+// config holds the configurations that dictate the behavior of a cache.
+type config struct {
+	// StdTtl defines the time-to-live for all the cache entries.
+	// The value `0` means unlimited.
+	stdTtl time.Duration
+	// deleteOnExpire defines whether the key should be automatically deleted when
+	// it expires or just flagged that its expired.
+	deleteOnExpire bool
+	// maxKeys defines the maximum number of keys the cache can store.
+	// If the cache exceeds this limit, an error will be thrown.
+	// The value `-1` means unlimited.
+	maxKeys int
+	// shards defines the number of shards a [ShardedCache] splits its keys across.
+	// It is only used by [NewSharded].
+	shards int
+	// newPolicy creates the eviction policy of the cache.
+	// The value `nil` means no eviction, new keys are rejected when the cache is full.
+	newPolicy func() EvictionPolicy
+	// maxCost defines the maximum total cost of the entries the cache can store.
+	// The value `-1` means unlimited.
+	maxCost int64
+	// sizer estimates the cost of the values set without an explicit cost.
+	sizer Sizer
+	// expiryResolution defines the granularity at which entries are deleted on expiry, entries
+	// expiring within the same interval are deleted together.
+	expiryResolution time.Duration
+	// cleanupInterval defines the interval at which expired entries are purged when they are not
+	// deleted on expiry.
+	// The value `0` means expired entries are never purged automatically.
+	cleanupInterval time.Duration
+	// slidingTtl defines whether the expiry date of the entries is pushed forward by their TTL every
+	// time they are read, so that they expire after a period of inactivity.
+	slidingTtl bool
+	// maxLifetime defines the maximum lifetime of the entries with a sliding TTL, past which they expire
+	// even if they are read.
+	// The value `0` means unlimited.
+	maxLifetime time.Duration
+	// refreshAhead defines the fraction of the TTL of a loaded entry after which it is reloaded in the
+	// background by GetOrLoad, while its current value is returned.
+	// The value `0` means entries are only loaded once expired.
+	refreshAhead float64
+	// staleWhileRevalidate defines how long an expired entry is returned by GetOrLoad while it is reloaded
+	// in the background.
+	staleWhileRevalidate time.Duration
+	// staleIfError defines how long an expired entry is returned by GetOrLoad when reloading it fails.
+	staleIfError time.Duration
+	// batchWindow defines how long GetManyOrLoad waits for the keys missing from concurrent calls to load
+	// them together.
+	// The value `0` means the keys missing from a call are loaded right away.
+	batchWindow time.Duration
+	// maxBatchSize defines the maximum number of keys loaded together by GetManyOrLoad.
+	// The value `0` means unlimited.
+	maxBatchSize int
+	// negativeTtl defines the time-to-live of the keys cached as not found by the loading methods.
+	// The value `0` means keys not found are not cached.
+	negativeTtl time.Duration
+	// earlyExpiration defines the beta parameter of the probabilistic early expiration of the loaded
+	// entries, a higher value makes the entries more likely to expire early.
+	// The value `0` means the entries never expire early.
+	earlyExpiration float64
+	// ttlJitter defines the fraction of the TTL by which the TTL of the entries is randomly spread.
+	// The value `0` means no jitter.
+	ttlJitter float64
+	// name identifies the cache in its metrics, see [MetricsHandler] and [WithExpvar].
+	name string
+	// expvar defines whether the statistics of the cache are published under expvar.
+	expvar bool
+	// codec encodes the values of the snapshots of the cache.
+	// The value `nil` means the values are encoded with gob.
+	codec Codec
+	// observer is notified before and after each operation on the cache.
+	observer Observer
+	// onEvicted is called with every entry removed from the cache and the reason of its removal.
+	onEvicted func(key string, value any, reason RemovalReason)
+	// clock is the source of the current time and of the timers of the cache.
+	clock Clock
+}
+
+// newConfig creates a new config with the default values, then applies the provided options on it.
+func newConfig(opts ...OptFunc) config {
+	cfg := config{
+		stdTtl:           0,
+		deleteOnExpire:   true,
+		maxKeys:          -1,
+		shards:           defaultShards,
+		maxCost:          -1,
+		sizer:            DefaultSizer,
+		expiryResolution: defaultExpiryResolution,
+		name:             defaultName,
+		clock:            realClock{},
+	}
+
+	for _, fn := range opts {
+		fn(&cfg)
+	}
+
+	return cfg
+}
 
 // WithStdTtl returns an [OptFunc] that sets the global cache's TTL (time-to-live).
 // It takes a duration as a parameter and updates the cache accordingly.
 // A duration of 0 means unlimited, the keys never expire.
 func WithStdTtl(stdTtl time.Duration) OptFunc {
-	return func(c *Cache) {
+	return func(c *config) {
 		if stdTtl > -1 {
 			c.stdTtl = stdTtl
 		}
@@ -20,7 +120,7 @@ func WithStdTtl(stdTtl time.Duration) OptFunc {
 // If set to true, entries will be automatically when they expire.
 // If set to false, entries will remain in the store but flagged as expired.
 func WithDeleteOnExpire(deleteOnExpire bool) OptFunc {
-	return func(c *Cache) {
+	return func(c *config) {
 		c.deleteOnExpire = deleteOnExpire
 	}
 }
@@ -28,9 +128,279 @@ func WithDeleteOnExpire(deleteOnExpire bool) OptFunc {
 // WithMaxKeys returns an [OptFunc] that sets the cache's maximum number of keys.
 // A value of -1 means unlimited keys.
 func WithMaxKeys(maxKeys int) OptFunc {
-	return func(c *Cache) {
+	return func(c *config) {
 		if maxKeys > -1 {
 			c.maxKeys = maxKeys
 		}
 	}
 }
+
+// WithShards returns an [OptFunc] that sets the number of shards of a [ShardedCache].
+// It has no effect on a [Cache] or a [SyncCache].
+// A value less than 1 is ignored.
+func WithShards(shards int) OptFunc {
+	return func(c *config) {
+		if shards > 0 {
+			c.shards = shards
+		}
+	}
+}
+
+// WithEvictionPolicy returns an [OptFunc] that sets the policy used to evict entries when the cache
+// has reached its maximum number of keys, instead of rejecting new keys with [ErrCacheFull].
+// It takes the constructor of the policy, such as [LRU], so that every cache gets its own instance.
+// A constructor of nil or [NoEviction] keeps the default behavior of rejecting new keys.
+func WithEvictionPolicy(newPolicy func() EvictionPolicy) OptFunc {
+	return func(c *config) {
+		c.newPolicy = newPolicy
+	}
+}
+
+// WithMaxCost returns an [OptFunc] that sets the cache's maximum total cost of its entries.
+// When the cache would exceed it, entries are evicted by the eviction policy, or the new entry is
+// rejected with [ErrCacheFull] if there is none.
+// A value of -1 means unlimited cost.
+func WithMaxCost(maxCost int64) OptFunc {
+	return func(c *config) {
+		if maxCost > -1 {
+			c.maxCost = maxCost
+		}
+	}
+}
+
+// WithSizer returns an [OptFunc] that sets the function estimating the cost of the values set
+// without an explicit cost. It defaults to [DefaultSizer].
+// A nil sizer is ignored.
+func WithSizer(sizer Sizer) OptFunc {
+	return func(c *config) {
+		if sizer != nil {
+			c.sizer = sizer
+		}
+	}
+}
+
+// WithExpiryResolution returns an [OptFunc] that sets the granularity at which entries are deleted on expiry.
+// Entries expiring within the same interval are deleted together in a single batch, so a coarser
+// resolution means fewer wake-ups, at the cost of keeping expired entries in the store for longer.
+// Expired entries are never returned, regardless of the resolution. It defaults to 10ms.
+// A value less than or equal to 0 is ignored.
+func WithExpiryResolution(resolution time.Duration) OptFunc {
+	return func(c *config) {
+		if resolution > 0 {
+			c.expiryResolution = resolution
+		}
+	}
+}
+
+// WithCleanupInterval returns an [OptFunc] that sets the interval at which expired entries are purged
+// from the cache when they are not deleted on expiry, see [WithDeleteOnExpire].
+// Without it, expired entries remain in the store until deleted, replaced or purged with PurgeExpired.
+// A value of 0 means expired entries are never purged automatically.
+func WithCleanupInterval(interval time.Duration) OptFunc {
+	return func(c *config) {
+		if interval > -1 {
+			c.cleanupInterval = interval
+		}
+	}
+}
+
+// WithClock returns an [OptFunc] that sets the clock the cache uses to get the current time and to
+// schedule the deletion of expired entries. It defaults to the system clock.
+// It is mostly useful in tests, to control the expiration of entries without waiting for it, see
+// the gocachetest package for a fake clock.
+// A nil clock is ignored.
+func WithClock(clock Clock) OptFunc {
+	return func(c *config) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// WithSlidingTtl returns an [OptFunc] that sets whether the TTL of the entries is sliding.
+// If set to true, the expiry date of an entry is pushed forward by its TTL every time it is read with
+// Get or Has, so that it expires after a period of inactivity rather than a fixed time after it is set.
+func WithSlidingTtl(slidingTtl bool) OptFunc {
+	return func(c *config) {
+		c.slidingTtl = slidingTtl
+	}
+}
+
+// WithMaxLifetime returns an [OptFunc] that sets the maximum lifetime of the entries with a sliding TTL.
+// An entry expires once its maximum lifetime has passed since it was set, however often it is read.
+// A duration of 0 means unlimited, negative durations are ignored.
+func WithMaxLifetime(maxLifetime time.Duration) OptFunc {
+	return func(c *config) {
+		if maxLifetime > -1 {
+			c.maxLifetime = maxLifetime
+		}
+	}
+}
+
+// WithOnEvicted returns an [OptFunc] that sets the callback called with every entry removed from the cache,
+// whether it expired, was deleted, replaced, evicted or cleared, along with the reason of its removal.
+// The callback is called once the cache's lock is released, so it may call back into the cache, in the
+// goroutine that removed the entry or, for a cache safe for concurrent use, in the goroutine of the expiry
+// timer. The keys of a [TypedCache] that are not strings are formatted with [fmt.Sprint].
+func WithOnEvicted(onEvicted func(key string, value any, reason RemovalReason)) OptFunc {
+	return func(c *config) {
+		c.onEvicted = onEvicted
+	}
+}
+
+// WithRefreshAhead returns an [OptFunc] that sets the fraction of the TTL after which an entry is
+// reloaded in the background by GetOrLoad, while its current value keeps being returned.
+// For instance, a threshold of 0.8 reloads an entry with a TTL of 10 minutes once it is 8 minutes old.
+// Thresholds outside of the range (0, 1) disable the refresh ahead.
+func WithRefreshAhead(threshold float64) OptFunc {
+	return func(c *config) {
+		if threshold > 0 && threshold < 1 {
+			c.refreshAhead = threshold
+		} else {
+			c.refreshAhead = 0
+		}
+	}
+}
+
+// WithStaleWhileRevalidate returns an [OptFunc] that sets how long an expired entry keeps being returned
+// by GetOrLoad while it is reloaded in the background.
+// Expired entries are kept in the store for that duration, but are not returned by the other methods,
+// though Len counts them.
+// A duration of 0 disables it, negative durations are ignored.
+func WithStaleWhileRevalidate(d time.Duration) OptFunc {
+	return func(c *config) {
+		if d > -1 {
+			c.staleWhileRevalidate = d
+		}
+	}
+}
+
+// WithStaleIfError returns an [OptFunc] that sets how long an expired entry is returned by GetOrLoad
+// when reloading it fails, instead of the error of the loader.
+// Expired entries are kept in the store for that duration, but are not returned by the other methods,
+// though Len counts them.
+// A duration of 0 disables it, negative durations are ignored.
+func WithStaleIfError(d time.Duration) OptFunc {
+	return func(c *config) {
+		if d > -1 {
+			c.staleIfError = d
+		}
+	}
+}
+
+// staleFor returns how long expired entries are kept in the store to be returned stale by GetOrLoad.
+func (c *config) staleFor() time.Duration {
+	if c.staleWhileRevalidate > c.staleIfError {
+		return c.staleWhileRevalidate
+	}
+
+	return c.staleIfError
+}
+
+// WithBatchWindow returns an [OptFunc] that sets how long GetManyOrLoad waits for the keys missing from
+// concurrent calls, to load them together with a single call to the batch loader.
+// A duration of 0 means the keys missing from a call are loaded right away, negative durations are ignored.
+func WithBatchWindow(window time.Duration) OptFunc {
+	return func(c *config) {
+		if window > -1 {
+			c.batchWindow = window
+		}
+	}
+}
+
+// WithMaxBatchSize returns an [OptFunc] that sets the maximum number of keys loaded together by GetManyOrLoad.
+// A batch is loaded as soon as it is full, without waiting for the batch window to elapse.
+// A size of 0 means unlimited, negative sizes are ignored.
+func WithMaxBatchSize(size int) OptFunc {
+	return func(c *config) {
+		if size > -1 {
+			c.maxBatchSize = size
+		}
+	}
+}
+
+// WithNegativeTtl returns an [OptFunc] that sets the time-to-live of the keys cached as not found.
+// When the loader of GetOrLoad returns an error wrapping [ErrKeyNotFound], or the batch loader of
+// GetManyOrLoad omits a key, the key is cached as not found for that duration, during which Get and the
+// loading methods return [ErrNegativeCached] without calling the loader. These keys are not returned by Keys,
+// and they are dropped to make room for a value when the cache is full.
+// A duration of 0 means keys not found are not cached, negative durations are ignored.
+func WithNegativeTtl(negativeTtl time.Duration) OptFunc {
+	return func(c *config) {
+		if negativeTtl > -1 {
+			c.negativeTtl = negativeTtl
+		}
+	}
+}
+
+// WithEarlyExpiration returns an [OptFunc] that enables the probabilistic early expiration of the loaded
+// entries, following the XFetch algorithm, with the provided beta parameter.
+// Get and GetOrLoad may consider an entry loaded by GetOrLoad or GetManyOrLoad expired slightly before
+// its expiry date, with a probability that grows as the expiry date gets closer and with the time it took
+// to load the entry, so that popular entries are reloaded by a single caller before they expire for all
+// of them. GetOrLoad returns the current value of such an entry while reloading it in the background.
+// A beta of 1 is a sensible default, higher values expire the entries earlier. A beta of 0 or less disables it.
+func WithEarlyExpiration(beta float64) OptFunc {
+	return func(c *config) {
+		if beta > 0 {
+			c.earlyExpiration = beta
+		} else {
+			c.earlyExpiration = 0
+		}
+	}
+}
+
+// WithTtlJitter returns an [OptFunc] that randomly spreads the TTL of the entries by up to the provided
+// percentage of their TTL in either direction, so that entries set at the same time do not all expire at
+// the same time. For instance, a jitter of 10 sets a TTL of 10 minutes to between 9 and 11 minutes.
+// It does not apply to the entries set with an expiry date. Percentages outside of the range (0, 100) disable it.
+func WithTtlJitter(pct float64) OptFunc {
+	return func(c *config) {
+		if pct > 0 && pct < 100 {
+			c.ttlJitter = pct / 100
+		} else {
+			c.ttlJitter = 0
+		}
+	}
+}
+
+// WithName returns an [OptFunc] that sets the name of the cache, which distinguishes it from the other
+// caches of the process in its metrics, as the name label of [MetricsHandler] and the key under which
+// [WithExpvar] publishes its statistics.
+// The name defaults to "default", empty names are ignored.
+func WithName(name string) OptFunc {
+	return func(c *config) {
+		if name != "" {
+			c.name = name
+		}
+	}
+}
+
+// WithExpvar returns an [OptFunc] that sets whether the statistics of the cache are published under
+// expvar, in the "gocache" map under the name of the cache, see [WithName].
+// A cache published under the name of another cache replaces it. A published cache is referenced by
+// expvar for the lifetime of the process.
+func WithExpvar(publish bool) OptFunc {
+	return func(c *config) {
+		c.expvar = publish
+	}
+}
+
+// WithObserver returns an [OptFunc] that sets the observer of the cache, notified before and after each
+// operation on the cache with the key, the outcome and the duration of the operation, see [Observer].
+// The operations of the loaders are reported as well. [NewLatencyObserver] creates an observer recording
+// the latency of each operation in a histogram.
+func WithObserver(observer Observer) OptFunc {
+	return func(c *config) {
+		c.observer = observer
+	}
+}
+
+// WithCodec returns an [OptFunc] that sets the codec encoding the values of the snapshots of the cache,
+// see [Codec]. A snapshot saved with a codec must be loaded by a cache with the same codec.
+// A nil codec means the values are encoded with gob, along with the rest of the snapshot.
+func WithCodec(codec Codec) OptFunc {
+	return func(c *config) {
+		c.codec = codec
+	}
+}
@@ -1,6 +1,7 @@
 package gocache
 
 import (
+	"sync"
 	"testing"
 	"time"
 )
@@ -86,3 +87,553 @@ func TestDeleteOnExpireOpts(t *testing.T) {
 		})
 	}
 }
+
+var shardsTestCases = []struct {
+	label    string
+	opt      OptFunc
+	expected int
+}{
+	{"without opts", nil, defaultShards},
+	{"negative shards", WithShards(-1), defaultShards},
+	{"zero shards", WithShards(0), defaultShards},
+	{"positive shards", WithShards(4), 4},
+}
+
+func TestShardsOpts(t *testing.T) {
+	for _, tc := range shardsTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.shards != tc.expected {
+				t.Errorf("shards - got: %v, want: %v", c.shards, tc.expected)
+			}
+		})
+	}
+}
+
+var evictionPolicyTestCases = []struct {
+	label    string
+	opt      OptFunc
+	expected bool
+}{
+	{"without opts", nil, false},
+	{"nil policy", WithEvictionPolicy(nil), false},
+	{"no eviction policy", WithEvictionPolicy(NoEviction), false},
+	{"lru policy", WithEvictionPolicy(LRU), true},
+}
+
+func TestEvictionPolicyOpts(t *testing.T) {
+	for _, tc := range evictionPolicyTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if (c.policy != nil) != tc.expected {
+				t.Errorf("policy set - got: %v, want: %v", c.policy != nil, tc.expected)
+			}
+		})
+	}
+}
+
+var maxCostTestCases = []struct {
+	label    string
+	opt      OptFunc
+	expected int64
+}{
+	{"without opts", nil, -1},
+	{"negative max cost", WithMaxCost(-10), -1},
+	{"zero max cost", WithMaxCost(0), 0},
+	{"positive max cost", WithMaxCost(1024), 1024},
+}
+
+func TestMaxCostOpts(t *testing.T) {
+	for _, tc := range maxCostTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.maxCost != tc.expected {
+				t.Errorf("maxCost - got: %v, want: %v", c.maxCost, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSizerOpts(t *testing.T) {
+	c := New(WithSizer(nil))
+
+	if cost := c.sizer("value"); cost != 5 {
+		t.Errorf("default sizer cost - got: %d, want: 5", cost)
+	}
+
+	c = New(WithSizer(func(any) int64 { return 42 }))
+
+	if cost := c.sizer("value"); cost != 42 {
+		t.Errorf("custom sizer cost - got: %d, want: 42", cost)
+	}
+}
+
+var expiryResolutionTestCases = []struct {
+	label    string
+	opt      OptFunc
+	expected time.Duration
+}{
+	{"without opts", nil, defaultExpiryResolution},
+	{"negative resolution", WithExpiryResolution(-1), defaultExpiryResolution},
+	{"zero resolution", WithExpiryResolution(0), defaultExpiryResolution},
+	{"positive resolution", WithExpiryResolution(time.Second), time.Second},
+}
+
+func TestExpiryResolutionOpts(t *testing.T) {
+	for _, tc := range expiryResolutionTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.expiryResolution != tc.expected {
+				t.Errorf("expiryResolution - got: %v, want: %v", c.expiryResolution, tc.expected)
+			}
+		})
+	}
+}
+
+var cleanupIntervalTestCases = []struct {
+	label    string
+	opt      OptFunc
+	expected time.Duration
+}{
+	{"without opts", nil, 0},
+	{"negative interval", WithCleanupInterval(-1), 0},
+	{"positive interval", WithCleanupInterval(time.Minute), time.Minute},
+}
+
+func TestCleanupIntervalOpts(t *testing.T) {
+	for _, tc := range cleanupIntervalTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.cleanupInterval != tc.expected {
+				t.Errorf("cleanupInterval - got: %v, want: %v", c.cleanupInterval, tc.expected)
+			}
+		})
+	}
+}
+
+func TestClockOpts(t *testing.T) {
+	// Test Case 1: Defaults to the system clock
+	t.Run("without opts", func(t *testing.T) {
+		if _, ok := New().clock.(realClock); !ok {
+			t.Errorf("clock - got: %T, want: realClock", New().clock)
+		}
+	})
+
+	// Test Case 2: Nil clock is ignored
+	t.Run("nil clock", func(t *testing.T) {
+		if _, ok := New(WithClock(nil)).clock.(realClock); !ok {
+			t.Errorf("clock - got: %T, want: realClock", New(WithClock(nil)).clock)
+		}
+	})
+
+	// Test Case 3: Custom clock
+	t.Run("custom clock", func(t *testing.T) {
+		clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+		c := New(WithClock(clk))
+		c.SetWithTtl("k1", "value1", time.Minute)
+
+		if c.clock != Clock(clk) {
+			t.Errorf("clock - got: %v, want: %v", c.clock, clk)
+		}
+
+		if !c.data["k1"].expiryDate.Equal(clk.now.Add(time.Minute)) {
+			t.Errorf("expiryDate - got: %v, want: %v", c.data["k1"].expiryDate, clk.now.Add(time.Minute))
+		}
+	})
+}
+
+// stubClock is a [Clock] that only moves when advanced, whose timers never fire.
+type stubClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (s *stubClock) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.now
+}
+
+func (s *stubClock) AfterFunc(time.Duration, func()) Timer {
+	return stubTimer{}
+}
+
+func (s *stubClock) advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = s.now.Add(d)
+}
+
+// stubTimer is a [Timer] that never fires.
+type stubTimer struct{}
+
+func (stubTimer) Stop() bool {
+	return false
+}
+
+func (stubTimer) Reset(time.Duration) bool {
+	return false
+}
+
+var slidingTtlTestCases = []struct {
+	label    string
+	opt      OptFunc
+	expected bool
+}{
+	{"without opts", nil, false},
+	{"sliding ttl", WithSlidingTtl(true), true},
+	{"fixed ttl", WithSlidingTtl(false), false},
+}
+
+func TestSlidingTtlOpts(t *testing.T) {
+	for _, tc := range slidingTtlTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.slidingTtl != tc.expected {
+				t.Errorf("slidingTtl - got: %v, want: %v", c.slidingTtl, tc.expected)
+			}
+		})
+	}
+}
+
+var maxLifetimeTestCases = []struct {
+	label    string
+	opt      OptFunc
+	expected time.Duration
+}{
+	{"without opts", nil, 0},
+	{"negative lifetime", WithMaxLifetime(-1), 0},
+	{"positive lifetime", WithMaxLifetime(time.Hour), time.Hour},
+}
+
+func TestMaxLifetimeOpts(t *testing.T) {
+	for _, tc := range maxLifetimeTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.maxLifetime != tc.expected {
+				t.Errorf("maxLifetime - got: %v, want: %v", c.maxLifetime, tc.expected)
+			}
+		})
+	}
+}
+
+var refreshAheadTestCases = []struct {
+	label    string
+	opt      OptFunc
+	expected float64
+}{
+	{"without opts", nil, 0},
+	{"zero threshold", WithRefreshAhead(0), 0},
+	{"threshold too large", WithRefreshAhead(1), 0},
+	{"valid threshold", WithRefreshAhead(0.8), 0.8},
+}
+
+func TestRefreshAheadOpts(t *testing.T) {
+	for _, tc := range refreshAheadTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.refreshAhead != tc.expected {
+				t.Errorf("refreshAhead - got: %v, want: %v", c.refreshAhead, tc.expected)
+			}
+		})
+	}
+}
+
+func TestStaleOpts(t *testing.T) {
+	// Test Case 1: Defaults
+	t.Run("without opts", func(t *testing.T) {
+		if c := New(); c.staleFor() != 0 {
+			t.Errorf("staleFor - got: %v, want: 0", c.staleFor())
+		}
+	})
+
+	// Test Case 2: Negative durations are ignored
+	t.Run("negative durations", func(t *testing.T) {
+		c := New(WithStaleWhileRevalidate(-1), WithStaleIfError(-1))
+
+		if c.staleWhileRevalidate != 0 || c.staleIfError != 0 {
+			t.Errorf("stale durations - got: %v, %v, want: 0, 0", c.staleWhileRevalidate, c.staleIfError)
+		}
+	})
+
+	// Test Case 3: Entries are kept for the longest duration
+	t.Run("stale for", func(t *testing.T) {
+		c := New(WithStaleWhileRevalidate(time.Minute), WithStaleIfError(time.Hour))
+
+		if c.staleFor() != time.Hour {
+			t.Errorf("staleFor - got: %v, want: 1h", c.staleFor())
+		}
+	})
+}
+
+func TestBatchOpts(t *testing.T) {
+	// Test Case 1: Defaults
+	t.Run("without opts", func(t *testing.T) {
+		if c := New(); c.batchWindow != 0 || c.maxBatchSize != 0 {
+			t.Errorf("batchWindow, maxBatchSize - got: %v, %d, want: 0, 0", c.batchWindow, c.maxBatchSize)
+		}
+	})
+
+	// Test Case 2: Negative values are ignored
+	t.Run("negative values", func(t *testing.T) {
+		if c := New(WithBatchWindow(-1), WithMaxBatchSize(-1)); c.batchWindow != 0 || c.maxBatchSize != 0 {
+			t.Errorf("batchWindow, maxBatchSize - got: %v, %d, want: 0, 0", c.batchWindow, c.maxBatchSize)
+		}
+	})
+
+	// Test Case 3: Batcher configured
+	t.Run("configured", func(t *testing.T) {
+		c := New(WithBatchWindow(time.Millisecond), WithMaxBatchSize(100))
+
+		if c.batcher.batchWindow != time.Millisecond || c.batcher.maxBatchSize != 100 {
+			t.Errorf("batchWindow, maxBatchSize - got: %v, %d, want: 1ms, 100", c.batcher.batchWindow, c.batcher.maxBatchSize)
+		}
+	})
+}
+
+var negativeTtlTestCases = []struct {
+	label    string
+	opt      OptFunc
+	expected time.Duration
+}{
+	{"without opts", nil, 0},
+	{"negative ttl", WithNegativeTtl(-1), 0},
+	{"positive ttl", WithNegativeTtl(time.Minute), time.Minute},
+}
+
+func TestNegativeTtlOpts(t *testing.T) {
+	for _, tc := range negativeTtlTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.negativeTtl != tc.expected {
+				t.Errorf("negativeTtl - got: %v, want: %v", c.negativeTtl, tc.expected)
+			}
+		})
+	}
+}
+
+var earlyExpirationTestCases = []struct {
+	label    string
+	opt      OptFunc
+	expected float64
+}{
+	{"without opts", nil, 0},
+	{"negative beta", WithEarlyExpiration(-1), 0},
+	{"positive beta", WithEarlyExpiration(1.5), 1.5},
+}
+
+func TestEarlyExpirationOpts(t *testing.T) {
+	for _, tc := range earlyExpirationTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.earlyExpiration != tc.expected {
+				t.Errorf("earlyExpiration - got: %v, want: %v", c.earlyExpiration, tc.expected)
+			}
+
+			if (c.rnd != nil) != (tc.expected > 0) {
+				t.Errorf("rnd set - got: %v, want: %v", c.rnd != nil, tc.expected > 0)
+			}
+		})
+	}
+}
+
+var ttlJitterTestCases = []struct {
+	label    string
+	opt      OptFunc
+	expected float64
+}{
+	{"without opts", nil, 0},
+	{"negative jitter", WithTtlJitter(-10), 0},
+	{"jitter too large", WithTtlJitter(100), 0},
+	{"valid jitter", WithTtlJitter(10), 0.1},
+	{"valid jitter below 1%", WithTtlJitter(0.5), 0.005},
+}
+
+func TestTtlJitterOpts(t *testing.T) {
+	for _, tc := range ttlJitterTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.ttlJitter != tc.expected {
+				t.Errorf("ttlJitter - got: %v, want: %v", c.ttlJitter, tc.expected)
+			}
+		})
+	}
+}
+
+var nameTestCases = []struct {
+	label    string
+	opt      OptFunc
+	expected string
+}{
+	{"without opts", nil, "default"},
+	{"empty name", WithName(""), "default"},
+	{"valid name", WithName("users"), "users"},
+}
+
+func TestNameOpts(t *testing.T) {
+	for _, tc := range nameTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.Name() != tc.expected {
+				t.Errorf("name - got: %q, want: %q", c.Name(), tc.expected)
+			}
+		})
+	}
+}
+
+var expvarTestCases = []struct {
+	label    string
+	opt      OptFunc
+	expected bool
+}{
+	{"without opts", nil, false},
+	{"expvar disabled", WithExpvar(false), false},
+	{"expvar enabled", WithExpvar(true), true},
+}
+
+func TestExpvarOpts(t *testing.T) {
+	for _, tc := range expvarTestCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(WithName("expvar-opts"), tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.expvar != tc.expected {
+				t.Errorf("expvar - got: %v, want: %v", c.expvar, tc.expected)
+			}
+		})
+	}
+}
+
+func TestObserverOpts(t *testing.T) {
+	o := NewLatencyObserver()
+
+	testCases := []struct {
+		label    string
+		opt      OptFunc
+		expected Observer
+	}{
+		{"without opts", nil, nil},
+		{"nil observer", WithObserver(nil), nil},
+		{"valid observer", WithObserver(o), o},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.observer != tc.expected {
+				t.Errorf("observer - got: %v, want: %v", c.observer, tc.expected)
+			}
+		})
+	}
+}
+
+func TestCodecOpts(t *testing.T) {
+	testCases := []struct {
+		label    string
+		opt      OptFunc
+		expected Codec
+	}{
+		{"without opts", nil, nil},
+		{"nil codec", WithCodec(nil), nil},
+		{"bytes codec", WithCodec(BytesCodec()), BytesCodec()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			var c *Cache
+			if tc.opt != nil {
+				c = New(tc.opt)
+			} else {
+				c = New()
+			}
+
+			if c.codec != tc.expected {
+				t.Errorf("codec - got: %v, want: %v", c.codec, tc.expected)
+			}
+		})
+	}
+}
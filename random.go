@@ -0,0 +1,69 @@
+package gocache
+
+import (
+	"math/rand"
+	"time"
+)
+
+// randomPolicy is an [EvictionPolicy] that evicts a random entry.
+// Keys are kept in a dense slice with a lookup map from the key to its index, so that a key is
+// sampled, and removed by swapping it with the last one, in O(1).
+type randomPolicy struct {
+	keys    []any
+	indexes map[any]int
+	rnd     *rand.Rand
+}
+
+// Random creates an [EvictionPolicy] that evicts an entry sampled uniformly at random, regardless of
+// its usage.
+func Random() EvictionPolicy {
+	return &randomPolicy{
+		indexes: make(map[any]int),
+		rnd:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// OnInsert adds the key to the sampled keys.
+func (p *randomPolicy) OnInsert(key any) {
+	if _, ok := p.indexes[key]; ok {
+		return
+	}
+
+	p.indexes[key] = len(p.keys)
+	p.keys = append(p.keys, key)
+}
+
+// OnAccess does nothing, the usage of the keys is not tracked.
+func (p *randomPolicy) OnAccess(key any) {}
+
+// OnUpdate does nothing, the usage of the keys is not tracked.
+func (p *randomPolicy) OnUpdate(key any) {}
+
+// OnRemove removes the key from the sampled keys.
+func (p *randomPolicy) OnRemove(key any) {
+	i, ok := p.indexes[key]
+
+	if !ok {
+		return
+	}
+
+	last := len(p.keys) - 1
+	p.keys[i] = p.keys[last]
+	p.indexes[p.keys[i]] = i
+	p.keys[last] = nil
+	p.keys = p.keys[:last]
+
+	delete(p.indexes, key)
+}
+
+// Victim returns a random key.
+func (p *randomPolicy) Victim() (any, bool) {
+	if len(p.keys) == 0 {
+		return nil, false
+	}
+
+	key := p.keys[p.rnd.Intn(len(p.keys))]
+	p.OnRemove(key)
+
+	return key, true
+}
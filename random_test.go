@@ -0,0 +1,59 @@
+package gocache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRandomPolicy(t *testing.T) {
+	p := Random()
+
+	for i := 0; i < 10; i++ {
+		p.OnInsert(i)
+	}
+
+	p.OnRemove(3)
+	p.OnRemove(9)
+
+	seen := make(map[any]bool)
+
+	for i := 0; i < 8; i++ {
+		key, ok := p.Victim()
+
+		if !ok {
+			t.Fatalf("Victim %d - got: false, want: true", i)
+		}
+
+		if key == 3 || key == 9 {
+			t.Errorf("Victim - got: removed key %v", key)
+		}
+
+		if seen[key] {
+			t.Errorf("Victim - got: %v twice", key)
+		}
+
+		seen[key] = true
+	}
+
+	if key, ok := p.Victim(); ok {
+		t.Errorf("Victim - got: %v, %v, want: nil, false", key, ok)
+	}
+}
+
+func TestCacheRandomEviction(t *testing.T) {
+	c := New(WithMaxKeys(5), WithEvictionPolicy(Random))
+
+	for i := 0; i < 20; i++ {
+		if err := c.Set(fmt.Sprintf("k%d", i), i); err != nil {
+			t.Errorf("Set k%d: err - got: %v, want: nil", i, err)
+		}
+	}
+
+	if keys := c.Keys(); len(keys) != 5 {
+		t.Errorf("keys length - got: %d, want: 5", len(keys))
+	}
+
+	if !c.Has("k19") {
+		t.Error("Has k19 - got: false, want: true")
+	}
+}
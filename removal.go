@@ -0,0 +1,89 @@
+package gocache
+
+import "fmt"
+
+// RemovalReason is the reason an entry was removed from the cache, passed to the callback set with [WithOnEvicted].
+type RemovalReason int
+
+const (
+	// Expired means the entry was removed because it expired.
+	Expired RemovalReason = iota
+	// Deleted means the entry was removed by a call to Delete, GetAndDelete or ChangeTtl.
+	Deleted
+	// Replaced means the value of the entry was replaced by a new value set for its key.
+	Replaced
+	// Evicted means the entry was evicted by the eviction policy to make room for another entry.
+	Evicted
+	// Cleared means the entry was removed by a call to Clear.
+	Cleared
+)
+
+// String returns the name of the removal reason.
+func (r RemovalReason) String() string {
+	switch r {
+	case Expired:
+		return "expired"
+	case Deleted:
+		return "deleted"
+	case Replaced:
+		return "replaced"
+	case Evicted:
+		return "evicted"
+	case Cleared:
+		return "cleared"
+	default:
+		return fmt.Sprintf("RemovalReason(%d)", int(r))
+	}
+}
+
+// removal is an entry removed from the cache, waiting to be notified.
+type removal[K comparable, V any] struct {
+	key    K
+	value  V
+	reason RemovalReason
+}
+
+// removed records the removal of the entry for the provided reason, to notify once the write lock is released.
+// The removal is counted in the statistics of the cache. Nothing is recorded for the entries caching the
+// absence of a value.
+func (c *TypedCache[K, V]) removed(val *cacheValue[K, V], reason RemovalReason) {
+	if val.negative {
+		return
+	}
+
+	c.stats.removed(reason)
+
+	if c.onEvicted != nil {
+		c.removals = append(c.removals, removal[K, V]{key: val.key, value: val.value, reason: reason})
+	}
+
+	// a replaced value is published along with its new value by the update event
+	if eventType, ok := removalEvents[reason]; ok {
+		c.published(TypedEvent[K, V]{Type: eventType, Key: val.key, OldValue: val.value})
+	}
+}
+
+// removalEvents maps the removal reasons to the type of the event published for the removal.
+var removalEvents = map[RemovalReason]EventType{
+	Expired: EventExpire,
+	Deleted: EventDelete,
+	Evicted: EventEvict,
+	Cleared: EventDelete,
+}
+
+// notifyRemovals calls the removal callback of the cache for each of the provided removals, in order.
+// It must be called without holding the lock of the cache.
+func (c *TypedCache[K, V]) notifyRemovals(removals []removal[K, V]) {
+	for _, r := range removals {
+		c.onEvicted(keyString(r.key), r.value, r.reason)
+	}
+}
+
+// keyString returns the provided key as a string, formatting it with [fmt.Sprint] if it is not a string.
+func keyString(key any) string {
+	if s, ok := key.(string); ok {
+		return s
+	}
+
+	return fmt.Sprint(key)
+}
@@ -0,0 +1,183 @@
+package gocache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// removalRecorder records the removals notified by a cache.
+type removalRecorder struct {
+	removals []string
+}
+
+func (r *removalRecorder) onEvicted(key string, value any, reason RemovalReason) {
+	r.removals = append(r.removals, fmt.Sprintf("%s=%v:%s", key, value, reason))
+}
+
+// take returns the recorded removals and resets them.
+func (r *removalRecorder) take() []string {
+	removals := r.removals
+	r.removals = nil
+
+	return removals
+}
+
+func equalStrings(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestCacheOnEvicted(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	rec := &removalRecorder{}
+	c := New(
+		WithClock(clk),
+		WithOnEvicted(rec.onEvicted),
+		WithMaxKeys(3),
+		WithEvictionPolicy(FIFO),
+		WithDeleteOnExpire(false),
+	)
+
+	// Test Case 1: Replaced
+	t.Run("replaced", func(t *testing.T) {
+		c.Set("k1", "value1")
+		c.Set("k1", "new value1")
+
+		if got, want := rec.take(), []string{"k1=value1:replaced"}; !equalStrings(got, want) {
+			t.Errorf("removals - got: %v, want: %v", got, want)
+		}
+	})
+
+	// Test Case 2: Deleted
+	t.Run("deleted", func(t *testing.T) {
+		c.Set("k2", "value2")
+		c.Set("k3", "value3")
+		c.Delete("k1")
+		c.GetAndDelete("k2")
+		c.ChangeTtl("k3", -1)
+		c.Delete("k4")
+
+		want := []string{"k1=new value1:deleted", "k2=value2:deleted", "k3=value3:deleted"}
+		if got := rec.take(); !equalStrings(got, want) {
+			t.Errorf("removals - got: %v, want: %v", got, want)
+		}
+	})
+
+	// Test Case 3: Evicted
+	t.Run("evicted", func(t *testing.T) {
+		for i := 1; i <= 4; i++ {
+			c.Set(fmt.Sprintf("k%d", i), i)
+		}
+
+		if got, want := rec.take(), []string{"k1=1:evicted"}; !equalStrings(got, want) {
+			t.Errorf("removals - got: %v, want: %v", got, want)
+		}
+	})
+
+	// Test Case 4: Expired
+	t.Run("expired", func(t *testing.T) {
+		c.ChangeTtl("k2", time.Minute)
+		c.ChangeTtl("k3", time.Minute)
+		clk.advance(2 * time.Minute)
+
+		if got := rec.take(); len(got) != 0 {
+			t.Errorf("removals before purge - got: %v, want: []", got)
+		}
+
+		c.Set("k3", "new value3")
+		c.PurgeExpired()
+
+		if got, want := rec.take(), []string{"k3=3:expired", "k2=2:expired"}; !equalStrings(got, want) {
+			t.Errorf("removals - got: %v, want: %v", got, want)
+		}
+	})
+
+	// Test Case 5: Cleared
+	t.Run("cleared", func(t *testing.T) {
+		c.Clear()
+
+		got := rec.take()
+		if len(got) != 2 {
+			t.Errorf("removals - got: %v, want: 2 removals", got)
+		}
+
+		for _, r := range got {
+			if r != "k3=new value3:cleared" && r != "k4=4:cleared" {
+				t.Errorf("removal - got: %s, want: cleared", r)
+			}
+		}
+	})
+}
+
+func TestCacheOnEvictedTyped(t *testing.T) {
+	rec := &removalRecorder{}
+	c := NewTyped[int, string](WithOnEvicted(rec.onEvicted))
+	c.Set(42, "value")
+	c.Delete(42)
+
+	if got, want := rec.take(), []string{"42=value:deleted"}; !equalStrings(got, want) {
+		t.Errorf("removals - got: %v, want: %v", got, want)
+	}
+}
+
+func TestSyncCacheOnEvicted(t *testing.T) {
+	// Setup
+	removals := make(chan RemovalReason, 2)
+
+	var c *SyncCache
+	c = NewSync(WithExpiryResolution(time.Millisecond), WithOnEvicted(func(key string, value any, reason RemovalReason) {
+		// calling back into the cache must not deadlock
+		c.Has(key)
+		c.Set("last", key)
+
+		removals <- reason
+	}))
+
+	// Test Case 1: Expired on the expiry timer
+	t.Run("expired", func(t *testing.T) {
+		c.SetWithTtl("k1", "value1", 20*time.Millisecond)
+
+		select {
+		case reason := <-removals:
+			if reason != Expired {
+				t.Errorf("reason - got: %v, want: expired", reason)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("removal - got: none, want: expired")
+		}
+	})
+
+	// Test Case 2: Callback calling back into the cache
+	t.Run("reentrant", func(t *testing.T) {
+		c.Delete("last")
+
+		if reason := <-removals; reason != Deleted {
+			t.Errorf("reason - got: %v, want: deleted", reason)
+		}
+
+		if value, _ := c.Get("last"); value != "last" {
+			t.Errorf("Get last - got: %v, want: last", value)
+		}
+	})
+}
+
+func TestRemovalReasonString(t *testing.T) {
+	if s := Evicted.String(); s != "evicted" {
+		t.Errorf("String - got: %s, want: evicted", s)
+	}
+
+	if s := RemovalReason(42).String(); s != "RemovalReason(42)" {
+		t.Errorf("String - got: %s, want: RemovalReason(42)", s)
+	}
+}
@@ -0,0 +1,378 @@
+package gocache
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// defaultShards is the default number of shards of a [ShardedCache].
+const defaultShards = 16
+
+// ShardedCache is an in-memory key-value store that is safe for concurrent use by multiple goroutines.
+// Keys are hashed across a number of independently locked shards, which reduces lock contention
+// compared to a [SyncCache] under heavy concurrent access.
+// The maximum number of keys and the maximum cost, if set, are enforced across all the shards. An eviction
+// policy evicts the entries of the shard of a key to make room for it, so a key that does not fit once its
+// shard is emptied is rejected with [ErrCacheFull]. With an eviction policy, the maximum number of keys is
+// enforced by each shard instead, as the maximum divided by the number of shards, rounded up. The cache
+// then holds up to the number of shards times that many keys, for example 16 keys for a maximum of 4 keys
+// across 16 shards.
+type ShardedCache struct {
+	shards []*Cache
+	// keyCount is the number of keys stored across all the shards.
+	keyCount int64
+	// cost is the total cost of the entries stored across all the shards.
+	cost int64
+	// batcher groups the keys loaded by GetManyOrLoad across all the shards.
+	batcher *batcher[string, any]
+}
+
+// NewSharded creates a new [ShardedCache] instance with optional configurations and empty data stores.
+// The number of shards can be configured with [WithShards], it defaults to 16.
+func NewSharded(opts ...OptFunc) *ShardedCache {
+	cfg := newConfig(opts...)
+	sc := &ShardedCache{shards: make([]*Cache, cfg.shards), batcher: newBatcher[string, any](cfg)}
+	// the cache is published as a whole rather than shard by shard
+	shardOpts := append(opts[:len(opts):len(opts)], WithExpvar(false))
+
+	for i := range sc.shards {
+		c := NewTypedSync[string, any](shardOpts...)
+		c.keyCount = &sc.keyCount
+		c.sharedCost = &sc.cost
+		c.batcher = sc.batcher
+
+		if c.newPolicy != nil && c.maxKeys > 0 {
+			c.maxKeys = (c.maxKeys + len(sc.shards) - 1) / len(sc.shards)
+			c.resetPolicy()
+		}
+
+		sc.shards[i] = &Cache{TypedCache: c}
+	}
+
+	if cfg.expvar {
+		publishExpvar(cfg.name, sc.Stats)
+	}
+
+	return sc
+}
+
+// Set sets a key-value pair in the cache.
+// If an error occurs, it will be returned, otherwise nil will be returned.
+func (sc *ShardedCache) Set(key string, value any) error {
+	return sc.shard(key).Set(key, value)
+}
+
+// SetWithTtl sets a key-value pair in the cache with a TTL (time-to-live) in duration.
+// If an error occurs, it will be returned, otherwise nil will be returned.
+func (sc *ShardedCache) SetWithTtl(key string, value any, ttl time.Duration) error {
+	return sc.shard(key).SetWithTtl(key, value, ttl)
+}
+
+// SetWithCost sets a key-value pair in the cache with a cost and a TTL (time-to-live) in duration.
+// The cost counts against the maximum cost of the cache, a cost of 0 or less means the cost is estimated
+// by the sizer of the cache.
+// If an error occurs, it will be returned, otherwise nil will be returned.
+func (sc *ShardedCache) SetWithCost(key string, value any, cost int64, ttl time.Duration) error {
+	return sc.shard(key).SetWithCost(key, value, cost, ttl)
+}
+
+// SetWithSlidingTtl sets a key-value pair in the cache with a sliding TTL (time-to-live) in duration.
+// The expiry date of the entry is pushed forward by its TTL every time it is read with Get or Has, up
+// to its maximum lifetime, a maximum lifetime of 0 or less means the maximum lifetime of the cache is used.
+// If an error occurs, it will be returned, otherwise nil will be returned.
+func (sc *ShardedCache) SetWithSlidingTtl(key string, value any, ttl time.Duration, maxLifetime time.Duration) error {
+	return sc.shard(key).SetWithSlidingTtl(key, value, ttl, maxLifetime)
+}
+
+// Get returns the value associated with the provided key from the cache.
+// It returns the value if found in the cache.
+// If an error occurs, it will be returned, otherwise nil will be returned.
+func (sc *ShardedCache) Get(key string) (any, error) {
+	return sc.shard(key).Get(key)
+}
+
+// GetOrLoad returns the value associated with the provided key from the cache, or loads it with the
+// provided loader if not found, see [TypedCache.GetOrLoad].
+func (sc *ShardedCache) GetOrLoad(ctx context.Context, key string, loader func(ctx context.Context) (any, time.Duration, error)) (any, error) {
+	return sc.shard(key).GetOrLoad(ctx, key, loader)
+}
+
+// GetManyOrLoad returns the values associated with the provided keys from the cache, loading the keys
+// not found across all the shards with a single call to the provided batch loader, see [TypedCache.GetManyOrLoad].
+func (sc *ShardedCache) GetManyOrLoad(ctx context.Context, keys []string, batchLoader func(ctx context.Context, missing []string) (map[string]any, error)) (map[string]any, error) {
+	obs := sc.shards[0].observe(ctx, OpGetManyOrLoad, "")
+	values, err := getManyOrLoad(obs.ctx, keys, sc.batcher, func(key string) *TypedCache[string, any] { return sc.shard(key).TypedCache }, batchLoader)
+	obs.done(errOutcome(err))
+
+	return values, err
+}
+
+// GetAndDelete returns the value associated with the provided key from the cache and removes it.
+// It returns the value if found in the cache.
+// If an error occurs, it will be returned, otherwise nil will be returned.
+func (sc *ShardedCache) GetAndDelete(key string) (any, error) {
+	return sc.shard(key).GetAndDelete(key)
+}
+
+// Delete removes the entry associated with the provided key from the cache if it exists.
+// It returns the number of deleted items from the cache.
+func (sc *ShardedCache) Delete(key string) int {
+	return sc.shard(key).Delete(key)
+}
+
+// ChangeTtl changes the TTL associated with the provided key in the cache.
+// It returns a bool indicating whether a change in TTL has occurred or not.
+func (sc *ShardedCache) ChangeTtl(key string, ttl time.Duration) bool {
+	return sc.shard(key).ChangeTtl(key, ttl)
+}
+
+// GetTtl returns the TTL, as a duration, of the provided key in the cache.
+// It returns -1 if the key does not exist.
+func (sc *ShardedCache) GetTtl(key string) time.Duration {
+	return sc.shard(key).GetTtl(key)
+}
+
+// SetWithExpiry sets a key-value pair in the cache that expires at the provided date.
+// An expiry date that is not in the future expires the key instead, see [TypedCache.SetWithExpiry].
+// If an error occurs, it will be returned, otherwise nil will be returned.
+func (sc *ShardedCache) SetWithExpiry(key string, value any, expiryDate time.Time) error {
+	return sc.shard(key).SetWithExpiry(key, value, expiryDate)
+}
+
+// ExpireAt changes the expiry date of the provided key in the cache.
+// An expiry date that is not in the future expires the key, see [TypedCache.ExpireAt].
+// It returns a bool indicating whether a change in expiry has occurred or not.
+func (sc *ShardedCache) ExpireAt(key string, expiryDate time.Time) bool {
+	return sc.shard(key).ExpireAt(key, expiryDate)
+}
+
+// Persist removes the TTL of the provided key in the cache, so that it never expires.
+// It returns a bool indicating whether the key had a TTL that was removed or not.
+func (sc *ShardedCache) Persist(key string) bool {
+	return sc.shard(key).Persist(key)
+}
+
+// Touch restarts the TTL of the provided key in the cache, pushing its expiry date to its TTL from now.
+// It returns a bool indicating whether the key exists or not.
+func (sc *ShardedCache) Touch(key string) bool {
+	return sc.shard(key).Touch(key)
+}
+
+// TimeToLive returns the time left, as a duration, before the provided key in the cache expires.
+// It returns 0 if the key never expires, and -1 if the key does not exist.
+func (sc *ShardedCache) TimeToLive(key string) time.Duration {
+	return sc.shard(key).TimeToLive(key)
+}
+
+// Keys returns the list of keys, as a slice of string, across all the shards of the cache.
+func (sc *ShardedCache) Keys() []string {
+	obs := sc.shards[0].observe(context.Background(), OpKeys, "")
+	keys := make([]string, 0, sc.size())
+
+	for _, c := range sc.shards {
+		keys = append(keys, c.keys()...)
+	}
+
+	obs.done(OutcomeSuccess)
+
+	return keys
+}
+
+// Has returns a bool whether the key exists in the cache or not.
+func (sc *ShardedCache) Has(key string) bool {
+	return sc.shard(key).Has(key)
+}
+
+// Clear clears the cache by emptying the stores of all the shards.
+func (sc *ShardedCache) Clear() {
+	obs := sc.shards[0].observe(context.Background(), OpClear, "")
+
+	for _, c := range sc.shards {
+		c.clearAll()
+	}
+
+	obs.done(OutcomeSuccess)
+}
+
+// Len returns the number of keys stored across all the shards of the cache.
+func (sc *ShardedCache) Len() int {
+	obs := sc.shards[0].observe(context.Background(), OpLen, "")
+	count := sc.size()
+	obs.done(OutcomeSuccess)
+
+	return count
+}
+
+// size is Len, without reporting the operation to the observer of the cache.
+func (sc *ShardedCache) size() int {
+	return int(atomic.LoadInt64(&sc.keyCount))
+}
+
+// LiveKeys returns the list of keys, as a slice of string, of the entries that have not expired across
+// all the shards of the cache.
+func (sc *ShardedCache) LiveKeys() []string {
+	obs := sc.shards[0].observe(context.Background(), OpLiveKeys, "")
+	keys := make([]string, 0, sc.size())
+
+	for _, c := range sc.shards {
+		keys = append(keys, c.liveKeys()...)
+	}
+
+	obs.done(OutcomeSuccess)
+
+	return keys
+}
+
+// LiveLen returns the number of entries that have not expired across all the shards of the cache.
+func (sc *ShardedCache) LiveLen() int {
+	obs := sc.shards[0].observe(context.Background(), OpLiveLen, "")
+	count := 0
+
+	for _, c := range sc.shards {
+		count += c.liveLen()
+	}
+
+	obs.done(OutcomeSuccess)
+
+	return count
+}
+
+// PurgeExpired deletes all the expired entries from all the shards of the cache.
+// It returns the number of deleted entries.
+func (sc *ShardedCache) PurgeExpired() int {
+	obs := sc.shards[0].observe(context.Background(), OpPurgeExpired, "")
+	count := 0
+
+	for _, c := range sc.shards {
+		count += c.purge()
+	}
+
+	obs.done(OutcomeSuccess)
+
+	return count
+}
+
+// Cost returns the total cost of the entries stored across all the shards of the cache.
+func (sc *ShardedCache) Cost() int64 {
+	obs := sc.shards[0].observe(context.Background(), OpCost, "")
+	cost := atomic.LoadInt64(&sc.cost)
+	obs.done(OutcomeSuccess)
+
+	return cost
+}
+
+// Subscribe streams the changes made to all the shards of the cache whose keys match the provided glob
+// pattern, see [TypedCache.Subscribe].
+// It returns the channel of events and a function cancelling the subscription, which closes the channel.
+func (sc *ShardedCache) Subscribe(pattern string, opts ...SubscribeOptFunc) (<-chan Event, func()) {
+	s := newSubscriber[string, any](pattern, opts...)
+
+	for _, c := range sc.shards {
+		c.subscribe(s)
+	}
+
+	return s.events, func() {
+		for _, c := range sc.shards {
+			c.unsubscribe(s)
+		}
+
+		s.close()
+	}
+}
+
+// Stats returns a snapshot of the statistics of the cache, summed across all the shards.
+func (sc *ShardedCache) Stats() Stats {
+	var stats Stats
+
+	for _, c := range sc.shards {
+		stats = stats.add(c.Stats())
+	}
+
+	// the shards share the number of keys, which is counted once
+	stats.Keys = sc.size()
+
+	return stats
+}
+
+// ResetStats resets the statistics of all the shards of the cache, except for the number of keys.
+func (sc *ShardedCache) ResetStats() {
+	for _, c := range sc.shards {
+		c.ResetStats()
+	}
+}
+
+// SaveTo writes a snapshot of the entries of all the shards of the cache to the provided writer, to be
+// loaded with LoadFrom, see [TypedCache.SaveTo].
+func (sc *ShardedCache) SaveTo(w io.Writer) error {
+	obs := sc.shards[0].observe(context.Background(), OpSaveTo, "")
+	err := sc.saveTo(w)
+	obs.done(errOutcome(err))
+
+	return err
+}
+
+// saveTo is SaveTo, without reporting the operation to the observer of the cache.
+func (sc *ShardedCache) saveTo(w io.Writer) error {
+	var entries []snapshotEntry[string, any]
+
+	for _, c := range sc.shards {
+		shardEntries, err := c.snapshot()
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, shardEntries...)
+	}
+
+	return saveSnapshot(w, entries)
+}
+
+// LoadFrom loads the entries of a snapshot saved by SaveTo from the provided reader into the shards of
+// the cache, see [TypedCache.LoadFrom]. The snapshot may have been saved by a cache with a different
+// number of shards, or by a [Cache].
+func (sc *ShardedCache) LoadFrom(r io.Reader) error {
+	obs := sc.shards[0].observe(context.Background(), OpLoadFrom, "")
+	err := sc.loadFrom(r)
+	obs.done(errOutcome(err))
+
+	return err
+}
+
+// loadFrom is LoadFrom, without reporting the operation to the observer of the cache.
+func (sc *ShardedCache) loadFrom(r io.Reader) error {
+	entries, err := readSnapshot[string, any](r, sc.shards[0].codec)
+	if err != nil {
+		return err
+	}
+
+	return restoreSnapshot(entries, func(key string) *TypedCache[string, any] { return sc.shard(key).TypedCache })
+}
+
+// Name returns the name of the cache, see [WithName].
+func (sc *ShardedCache) Name() string {
+	return sc.shards[0].Name()
+}
+
+// shard returns the shard responsible for the provided key.
+func (sc *ShardedCache) shard(key string) *Cache {
+	return sc.shards[fnv32(key)%uint32(len(sc.shards))]
+}
+
+// fnv32 returns the 32-bit FNV-1a hash of the provided key.
+// It is inlined here instead of using hash/fnv to avoid allocating on every lookup.
+func fnv32(key string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+
+	hash := uint32(offset32)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= prime32
+	}
+
+	return hash
+}
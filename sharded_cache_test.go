@@ -0,0 +1,252 @@
+package gocache
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShardedCacheShards(t *testing.T) {
+	// Test Case 1: Default number of shards
+	t.Run("default shards", func(t *testing.T) {
+		sc := NewSharded()
+
+		if len(sc.shards) != defaultShards {
+			t.Errorf("shards - got: %d, want: %d", len(sc.shards), defaultShards)
+		}
+	})
+
+	// Test Case 2: Configured number of shards
+	t.Run("configured shards", func(t *testing.T) {
+		sc := NewSharded(WithShards(4), WithStdTtl(time.Second))
+
+		if len(sc.shards) != 4 {
+			t.Errorf("shards - got: %d, want: 4", len(sc.shards))
+		}
+
+		for i, c := range sc.shards {
+			if c.stdTtl != time.Second {
+				t.Errorf("shard %d stdTtl - got: %v, want: 1s", i, c.stdTtl)
+			}
+		}
+	})
+}
+
+func TestShardedCacheMaxKeys(t *testing.T) {
+	// Setup
+	sc := NewSharded(WithShards(8), WithMaxKeys(10))
+
+	// Test Case 1: Maximum number of keys enforced across shards
+	t.Run("max keys across shards", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			if err := sc.Set(fmt.Sprintf("k%d", i), i); err != nil {
+				t.Errorf("Set k%d: err - got: %v, want: nil", i, err)
+			}
+		}
+
+		for i := 10; i < 20; i++ {
+			if err := sc.Set(fmt.Sprintf("k%d", i), i); !errors.Is(err, ErrCacheFull) {
+				t.Errorf("Set k%d: err - got: %v, want: ErrCacheFull", i, err)
+			}
+		}
+	})
+
+	// Test Case 2: Updating an existing key when full
+	t.Run("existing key when full", func(t *testing.T) {
+		if err := sc.Set("k0", "new value0"); err != nil {
+			t.Errorf("Set k0: err - got: %v, want: nil", err)
+		}
+
+		if sc.Len() != 10 {
+			t.Errorf("Len - got: %d, want: 10", sc.Len())
+		}
+	})
+
+	// Test Case 3: Deleting frees a slot in any shard
+	t.Run("delete frees slot", func(t *testing.T) {
+		sc.Delete("k0")
+		sc.GetAndDelete("k1")
+		sc.ChangeTtl("k2", -1)
+
+		for i := 20; i < 23; i++ {
+			if err := sc.Set(fmt.Sprintf("k%d", i), i); err != nil {
+				t.Errorf("Set k%d: err - got: %v, want: nil", i, err)
+			}
+		}
+
+		if sc.Len() != 10 {
+			t.Errorf("Len - got: %d, want: 10", sc.Len())
+		}
+	})
+
+	// Test Case 4: Expiring frees a slot
+	t.Run("expire frees slot", func(t *testing.T) {
+		sc.ChangeTtl("k3", 50*time.Millisecond)
+
+		time.Sleep(100 * time.Millisecond)
+
+		if sc.Len() != 9 {
+			t.Errorf("Len - got: %d, want: 9", sc.Len())
+		}
+	})
+
+	// Test Case 5: Concurrent inserts never exceed the maximum
+	t.Run("concurrent inserts", func(t *testing.T) {
+		sc.Clear()
+
+		var wg sync.WaitGroup
+
+		for g := 0; g < stressGoroutines; g++ {
+			wg.Add(1)
+
+			go func(g int) {
+				defer wg.Done()
+
+				for i := 0; i < 100; i++ {
+					sc.Set(fmt.Sprintf("g%d-%d", g, i), i)
+				}
+			}(g)
+		}
+
+		wg.Wait()
+
+		if keys := sc.Keys(); len(keys) != 10 || sc.Len() != 10 {
+			t.Errorf("keys length - got: %d (Len %d), want: 10", len(keys), sc.Len())
+		}
+	})
+}
+
+func TestShardedCacheKeysAndClear(t *testing.T) {
+	// Setup
+	sc := NewSharded(WithShards(4))
+
+	for i := 0; i < 100; i++ {
+		sc.Set(fmt.Sprintf("k%d", i), i)
+	}
+
+	// Test Case 1: Keys from all shards
+	t.Run("keys", func(t *testing.T) {
+		if keys := sc.Keys(); len(keys) != 100 {
+			t.Errorf("keys length - got: %d, want: 100", len(keys))
+		}
+
+		for i := 0; i < 100; i++ {
+			if value, err := sc.Get(fmt.Sprintf("k%d", i)); err != nil || value != i {
+				t.Errorf("Get k%d - got: %v, %v, want: %d, nil", i, value, err, i)
+			}
+		}
+	})
+
+	// Test Case 2: Clear all shards
+	t.Run("clear", func(t *testing.T) {
+		sc.Clear()
+
+		if keys := sc.Keys(); len(keys) != 0 {
+			t.Errorf("keys length - got: %d, want: 0", len(keys))
+		}
+
+		if sc.Len() != 0 {
+			t.Errorf("Len - got: %d, want: 0", sc.Len())
+		}
+	})
+}
+
+func TestShardedCacheConcurrentAccess(t *testing.T) {
+	sc := NewSharded(WithMaxKeys(keyPoolSize / 2))
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < stressGoroutines; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < stressIterations; i++ {
+				key := strconv.Itoa((g*stressIterations + i) % keyPoolSize)
+
+				switch i % 6 {
+				case 0:
+					sc.SetWithTtl(key, i, time.Millisecond)
+				case 1:
+					sc.Set(key, i)
+				case 2:
+					sc.Get(key)
+				case 3:
+					sc.Delete(key)
+				case 4:
+					sc.Keys()
+				case 5:
+					sc.ChangeTtl(key, time.Millisecond)
+				}
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	if sc.Len() > keyPoolSize/2 {
+		t.Errorf("Len - got: %d, want: at most %d", sc.Len(), keyPoolSize/2)
+	}
+}
+
+func BenchmarkCacheParallel(b *testing.B) {
+	keys := make([]string, keyPoolSize)
+	for i := 0; i < keyPoolSize; i++ {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	caches := []struct {
+		label string
+		cache interface {
+			Set(string, any) error
+			Get(string) (any, error)
+		}
+	}{
+		{"sync", NewSync()},
+		{"sharded", NewSharded()},
+	}
+
+	for _, cc := range caches {
+		for _, k := range keys {
+			cc.cache.Set(k, k)
+		}
+
+		b.Run(cc.label+"/get", func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					cc.cache.Get(keys[i%keyPoolSize])
+					i++
+				}
+			})
+		})
+
+		b.Run(cc.label+"/set", func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					cc.cache.Set(keys[i%keyPoolSize], i)
+					i++
+				}
+			})
+		})
+
+		b.Run(cc.label+"/mixed", func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					if i%4 == 0 {
+						cc.cache.Set(keys[i%keyPoolSize], i)
+					} else {
+						cc.cache.Get(keys[i%keyPoolSize])
+					}
+					i++
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package gocache
+
+import (
+	"fmt"
+	"math"
+)
+
+// sketchDepth is the number of rows, one counter per row for each key, of a [countMinSketch].
+const sketchDepth = 4
+
+// sketchSeeds are the seeds mixed with the hash of a key to get its counter in each row.
+var sketchSeeds = [sketchDepth]uint64{0xc3a5c85c97cb3127, 0xb492b66fbe98f273, 0x9ae16a3b2f90404f, 0xcbf29ce484222325}
+
+// countMinSketch is a probabilistic frequency estimator of keys.
+// Each key increments one 4-bit counter in each row, and its frequency is estimated as the minimum
+// of its counters, which can only be over estimated by hash collisions.
+// To keep the estimations fresh, all the counters are halved once the number of increments reaches
+// the sample size, so that keys that were popular a long time ago lose their weight.
+type countMinSketch struct {
+	// counters holds the rows of counters one after the other.
+	counters []uint8
+	// mask is used to get the index of a counter in a row, the width of a row being a power of two.
+	mask uint64
+	// additions is the number of increments since the last reset.
+	additions int
+	// sampleSize is the number of increments after which the counters are halved.
+	sampleSize int
+}
+
+// newCountMinSketch creates a sketch sized to estimate the frequencies of about capacity keys.
+func newCountMinSketch(capacity int) *countMinSketch {
+	width := 64
+	for width < capacity {
+		width <<= 1
+	}
+
+	return &countMinSketch{
+		counters:   make([]uint8, width*sketchDepth),
+		mask:       uint64(width - 1),
+		sampleSize: 10 * width,
+	}
+}
+
+// increment increments the counters of the key, halving all the counters when the sample size is reached.
+func (s *countMinSketch) increment(key any) {
+	hash := hashKey(key)
+	added := false
+
+	for i := 0; i < sketchDepth; i++ {
+		idx := s.index(hash, i)
+
+		if s.counters[idx] < 15 {
+			s.counters[idx]++
+			added = true
+		}
+	}
+
+	if added {
+		s.additions++
+
+		if s.additions >= s.sampleSize {
+			s.reset()
+		}
+	}
+}
+
+// estimate returns the estimated frequency of the key.
+func (s *countMinSketch) estimate(key any) int {
+	hash := hashKey(key)
+	freq := uint8(math.MaxUint8)
+
+	for i := 0; i < sketchDepth; i++ {
+		if c := s.counters[s.index(hash, i)]; c < freq {
+			freq = c
+		}
+	}
+
+	return int(freq)
+}
+
+// reset halves all the counters.
+func (s *countMinSketch) reset() {
+	for i := range s.counters {
+		s.counters[i] >>= 1
+	}
+
+	s.additions /= 2
+}
+
+// index returns the index of the counter of the hash in the provided row.
+func (s *countMinSketch) index(hash uint64, row int) int {
+	h := (hash ^ sketchSeeds[row]) * 0x9e3779b97f4a7c15
+	h ^= h >> 32
+
+	return row*int(s.mask+1) + int(h&s.mask)
+}
+
+// hashKey returns a 64-bit hash of a key of any comparable type.
+// Strings, integers, floats and booleans are hashed directly, other types are hashed through their
+// Go-syntax representation, which is slower but consistent for equal values.
+func hashKey(key any) uint64 {
+	switch k := key.(type) {
+	case string:
+		return fnv64(k)
+	case int:
+		return mix64(uint64(k))
+	case int8:
+		return mix64(uint64(k))
+	case int16:
+		return mix64(uint64(k))
+	case int32:
+		return mix64(uint64(k))
+	case int64:
+		return mix64(uint64(k))
+	case uint:
+		return mix64(uint64(k))
+	case uint8:
+		return mix64(uint64(k))
+	case uint16:
+		return mix64(uint64(k))
+	case uint32:
+		return mix64(uint64(k))
+	case uint64:
+		return mix64(k)
+	case uintptr:
+		return mix64(uint64(k))
+	case float32:
+		return mix64(uint64(math.Float32bits(k)))
+	case float64:
+		return mix64(math.Float64bits(k))
+	case bool:
+		if k {
+			return mix64(1)
+		}
+
+		return mix64(0)
+	default:
+		return fnv64(fmt.Sprintf("%T:%#v", key, key))
+	}
+}
+
+// fnv64 returns the 64-bit FNV-1a hash of the provided string.
+func fnv64(s string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+
+	hash := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		hash ^= uint64(s[i])
+		hash *= prime64
+	}
+
+	return hash
+}
+
+// mix64 spreads the bits of an integer, using the finalizer of SplitMix64.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
@@ -0,0 +1,32 @@
+package gocache
+
+import "time"
+
+// SetWithSlidingTtl sets a key-value pair in the cache with a sliding TTL (time-to-live) in duration.
+// The expiry date of the entry is pushed forward by its TTL every time it is read with Get or Has, so
+// that it expires after a period of inactivity. The entry expires once its maximum lifetime has passed
+// since it was set however often it is read, a maximum lifetime of 0 or less means the maximum lifetime
+// of the cache is used.
+// If an error occurs, it will be returned, otherwise nil will be returned.
+func (c *TypedCache[K, V]) SetWithSlidingTtl(key K, value V, ttl time.Duration, maxLifetime time.Duration) error {
+	if maxLifetime <= 0 {
+		maxLifetime = c.maxLifetime
+	}
+
+	return c.store(key, value, setOptions{ttl: ttl, sliding: true, maxLifetime: maxLifetime})
+}
+
+// slide pushes the expiry date of the entry forward by its TTL from the provided time if it has a sliding
+// TTL, without exceeding its deadline, and reschedules its expiry.
+// It requires the write lock.
+func (c *TypedCache[K, V]) slide(val *cacheValue[K, V], now time.Time) {
+	if !val.sliding {
+		return
+	}
+
+	val.expiryDate = val.expiryFrom(now)
+
+	if c.deleteOnExpire {
+		c.schedule(val)
+	}
+}
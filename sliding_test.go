@@ -0,0 +1,142 @@
+package gocache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCacheSlidingTtl(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := New(WithClock(clk))
+	c.SetWithSlidingTtl("session", "token", time.Minute, 0)
+	c.SetWithTtl("fixed", "value", time.Minute)
+
+	// Test Case 1: Reads push the expiry date forward
+	t.Run("extended on read", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			clk.advance(40 * time.Second)
+
+			if _, err := c.Get("session"); err != nil {
+				t.Errorf("Get session after %v: err - got: %v, want: nil", time.Duration(i+1)*40*time.Second, err)
+			}
+		}
+
+		if want := clk.now.Add(time.Minute); !c.data["session"].expiryDate.Equal(want) {
+			t.Errorf("expiryDate - got: %v, want: %v", c.data["session"].expiryDate, want)
+		}
+
+		if c.expiries[0].key != "fixed" {
+			t.Errorf("earliest expiry - got: %s, want: fixed", c.expiries[0].key)
+		}
+	})
+
+	// Test Case 2: Fixed TTL entries are not extended
+	t.Run("fixed ttl", func(t *testing.T) {
+		if c.Has("fixed") {
+			t.Errorf("has key fixed - got: true, want: false")
+		}
+	})
+
+	// Test Case 3: Has extends like Get
+	t.Run("extended on has", func(t *testing.T) {
+		clk.advance(50 * time.Second)
+
+		if !c.Has("session") {
+			t.Errorf("has key session - got: false, want: true")
+		}
+
+		clk.advance(50 * time.Second)
+
+		if !c.Has("session") {
+			t.Errorf("has key session - got: false, want: true")
+		}
+	})
+
+	// Test Case 4: Expires after a period of inactivity
+	t.Run("inactivity", func(t *testing.T) {
+		clk.advance(time.Minute + time.Second)
+
+		if _, err := c.Get("session"); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("Get session: err - got: %v, want: ErrKeyNotFound", err)
+		}
+	})
+}
+
+func TestCacheSlidingTtlMaxLifetime(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := New(WithClock(clk), WithSlidingTtl(true), WithMaxLifetime(3*time.Minute))
+	c.Set("unlimited", "value")
+	c.SetWithTtl("global", "value", time.Minute)
+	c.SetWithSlidingTtl("key", "value", time.Minute, 2*time.Minute)
+
+	// Test Case 1: Entries without a TTL do not slide
+	t.Run("no ttl", func(t *testing.T) {
+		if c.data["unlimited"].sliding {
+			t.Errorf("sliding - got: true, want: false")
+		}
+	})
+
+	// Test Case 2: Extensions are capped by the maximum lifetime
+	t.Run("capped", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			clk.advance(30 * time.Second)
+			c.Get("global")
+			c.Get("key")
+		}
+
+		if want := clk.now.Add(30 * time.Second); !c.data["key"].expiryDate.Equal(want) {
+			t.Errorf("expiryDate key - got: %v, want: %v", c.data["key"].expiryDate, want)
+		}
+
+		clk.advance(31 * time.Second)
+
+		if c.Has("key") {
+			t.Errorf("has key key - got: true, want: false")
+		}
+
+		if !c.Has("global") {
+			t.Errorf("has key global - got: false, want: true")
+		}
+	})
+
+	// Test Case 3: The cache's maximum lifetime applies by default
+	t.Run("global max lifetime", func(t *testing.T) {
+		clk.advance(29 * time.Second)
+		c.Get("global")
+
+		if want := clk.now.Add(30 * time.Second); !c.data["global"].expiryDate.Equal(want) {
+			t.Errorf("expiryDate global - got: %v, want: %v", c.data["global"].expiryDate, want)
+		}
+
+		clk.advance(31 * time.Second)
+
+		if c.Has("global") {
+			t.Errorf("has key global - got: true, want: false")
+		}
+	})
+}
+
+func TestSyncCacheSlidingTtl(t *testing.T) {
+	c := NewSync(WithExpiryResolution(time.Millisecond))
+	c.SetWithSlidingTtl("session", "token", 50*time.Millisecond, 0)
+
+	// Test Case 1: Deleted after a period of inactivity once reads stop
+	t.Run("deleted on expiry", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			time.Sleep(25 * time.Millisecond)
+
+			if !c.Has("session") {
+				t.Errorf("has key session after %d reads - got: false, want: true", i)
+			}
+		}
+
+		time.Sleep(100 * time.Millisecond)
+
+		if c.Len() != 0 {
+			t.Errorf("Len - got: %d, want: 0", c.Len())
+		}
+	})
+}
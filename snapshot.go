@@ -0,0 +1,269 @@
+package gocache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+)
+
+const (
+	// snapshotMagic identifies the data saved by SaveTo.
+	snapshotMagic = "GOCACHE\x00"
+	// snapshotVersion is the version of the format of the snapshots saved by SaveTo.
+	// The version 2 added the values encoded with a codec, the snapshots of the version 1 are still loaded.
+	snapshotVersion = 2
+	// snapshotMinVersion is the oldest version of the format of the snapshots loaded by LoadFrom.
+	snapshotMinVersion = 1
+	// snapshotHeaderSize is the size of the header of a snapshot, its magic followed by its version.
+	snapshotHeaderSize = len(snapshotMagic) + 2
+	// snapshotChecksumSize is the size of the checksum ending a snapshot.
+	snapshotChecksumSize = 4
+)
+
+// snapshotTable is the CRC-32 table of the checksum of the snapshots.
+var snapshotTable = crc32.MakeTable(crc32.Castagnoli)
+
+// snapshotEntry is an entry of a snapshot, its fields are exported for gob to encode them.
+type snapshotEntry[K comparable, V any] struct {
+	Key K
+	// Value is the value of the entry, unless encoded with a codec.
+	Value V
+	// Tag is the tag of the type of the value encoded with a codec, and Data its encoding.
+	Tag  string
+	Data []byte
+	Cost int64
+	Ttl  time.Duration
+	// ExpiryDate is the absolute expiry date of the entry, so that it keeps the time it has left once loaded.
+	ExpiryDate time.Time
+	Sliding    bool
+	Deadline   time.Time
+}
+
+// SaveTo writes a snapshot of the entries of the cache to the provided writer, to be loaded with LoadFrom.
+// The expired entries and the keys cached as not found are not saved. The entries keep their absolute
+// expiry date, so that they expire at the same time once loaded.
+//
+// The snapshot starts with a header holding its format version and ends with a checksum of its content.
+// The keys and values are encoded with gob, values of interface types must therefore be registered with
+// [gob.Register], unless the values are encoded with the codec of the cache, see [WithCodec].
+func (c *TypedCache[K, V]) SaveTo(w io.Writer) error {
+	obs := c.observe(context.Background(), OpSaveTo, "")
+	err := c.saveTo(w)
+	obs.done(errOutcome(err))
+
+	return err
+}
+
+// saveTo is SaveTo, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) saveTo(w io.Writer) error {
+	entries, err := c.snapshot()
+	if err != nil {
+		return err
+	}
+
+	return saveSnapshot(w, entries)
+}
+
+// LoadFrom loads the entries of a snapshot saved by SaveTo from the provided reader into the cache,
+// replacing the values of the keys already in the cache. The entries that have expired since the
+// snapshot was saved are skipped, the others keep their TTL and their expiry date.
+//
+// The snapshot is read and verified entirely before it is loaded, a snapshot that is not valid, saved
+// in an unsupported format version or corrupted is rejected with [ErrInvalidSnapshot], [ErrSnapshotVersion]
+// or [ErrSnapshotChecksum] and leaves the cache as is, as does a value that cannot be decoded by the
+// codec of the cache. Loading stops at the first entry that cannot be set in the cache, whose error is returned.
+func (c *TypedCache[K, V]) LoadFrom(r io.Reader) error {
+	obs := c.observe(context.Background(), OpLoadFrom, "")
+	err := c.loadFrom(r)
+	obs.done(errOutcome(err))
+
+	return err
+}
+
+// loadFrom is LoadFrom, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) loadFrom(r io.Reader) error {
+	entries, err := readSnapshot[K, V](r, c.codec)
+	if err != nil {
+		return err
+	}
+
+	return restoreSnapshot(entries, func(K) *TypedCache[K, V] { return c })
+}
+
+// snapshot returns the entries of the cache to save in a snapshot, with their values encoded with the
+// codec of the cache if any.
+func (c *TypedCache[K, V]) snapshot() ([]snapshotEntry[K, V], error) {
+	c.rLock()
+	defer c.rUnlock()
+
+	entries := make([]snapshotEntry[K, V], 0, len(c.data))
+	now := c.now()
+
+	for k, v := range c.data {
+		if !v.found(now) {
+			continue
+		}
+
+		e := snapshotEntry[K, V]{
+			Key:        k,
+			Value:      v.value,
+			Cost:       v.cost,
+			Ttl:        v.ttl,
+			ExpiryDate: v.expiryDate,
+			Sliding:    v.sliding,
+			Deadline:   v.deadline,
+		}
+
+		if c.codec != nil {
+			tag, data, err := c.codec.Marshal(v.value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode key %v: %w", k, err)
+			}
+
+			var zero V
+			e.Value, e.Tag, e.Data = zero, tag, data
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// saveSnapshot writes a snapshot of the provided entries to the provided writer: its header, the number
+// of entries and the entries encoded with gob, then the checksum of all of it.
+func saveSnapshot[K comparable, V any](w io.Writer, entries []snapshotEntry[K, V]) error {
+	checksum := crc32.New(snapshotTable)
+	bw := bufio.NewWriter(io.MultiWriter(w, checksum))
+
+	var header [snapshotHeaderSize]byte
+	copy(header[:], snapshotMagic)
+	binary.BigEndian.PutUint16(header[len(snapshotMagic):], snapshotVersion)
+
+	if _, err := bw.Write(header[:]); err != nil {
+		return err
+	}
+
+	enc := gob.NewEncoder(bw)
+
+	if err := enc.Encode(len(entries)); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("failed to encode key %v: %w", e.Key, err)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	var sum [snapshotChecksumSize]byte
+	binary.BigEndian.PutUint32(sum[:], checksum.Sum32())
+
+	_, err := w.Write(sum[:])
+
+	return err
+}
+
+// readSnapshot reads the snapshot from the provided reader, verifying its header and its checksum, and
+// returns its entries, with their values decoded with the provided codec if encoded with a codec.
+func readSnapshot[K comparable, V any](r io.Reader, codec Codec) ([]snapshotEntry[K, V], error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < snapshotHeaderSize+snapshotChecksumSize || string(data[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, ErrInvalidSnapshot
+	}
+
+	if version := binary.BigEndian.Uint16(data[len(snapshotMagic):]); version < snapshotMinVersion || version > snapshotVersion {
+		return nil, fmt.Errorf("%w %d, want %d to %d", ErrSnapshotVersion, version, snapshotMinVersion, snapshotVersion)
+	}
+
+	body := data[:len(data)-snapshotChecksumSize]
+	if binary.BigEndian.Uint32(data[len(body):]) != crc32.Checksum(body, snapshotTable) {
+		return nil, ErrSnapshotChecksum
+	}
+
+	dec := gob.NewDecoder(bytes.NewReader(body[snapshotHeaderSize:]))
+
+	var count int
+	if err := dec.Decode(&count); err != nil || count < 0 {
+		return nil, ErrInvalidSnapshot
+	}
+
+	// the number of entries is not trusted to allocate them all upfront
+	var entries []snapshotEntry[K, V]
+
+	for i := 0; i < count; i++ {
+		var e snapshotEntry[K, V]
+		if err := dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("failed to decode entry %d: %w", i, err)
+		}
+
+		if e.Tag != "" {
+			if err := decodeValue(&e, codec); err != nil {
+				return nil, fmt.Errorf("failed to decode key %v: %w", e.Key, err)
+			}
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// decodeValue decodes the value of the provided entry, encoded with a codec, with the provided codec.
+func decodeValue[K comparable, V any](e *snapshotEntry[K, V], codec Codec) error {
+	if codec == nil {
+		return errors.New("the value is encoded with a codec, see WithCodec")
+	}
+
+	decoded, err := codec.Unmarshal(e.Tag, e.Data)
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+	if e.Value, ok = decoded.(V); !ok && decoded != nil {
+		return fmt.Errorf("decoded a value of type %T, want %T", decoded, e.Value)
+	}
+
+	e.Tag, e.Data = "", nil
+
+	return nil
+}
+
+// restoreSnapshot sets the provided entries in the caches they belong to, skipping the expired entries.
+func restoreSnapshot[K comparable, V any](entries []snapshotEntry[K, V], cacheOf func(key K) *TypedCache[K, V]) error {
+	for _, e := range entries {
+		c := cacheOf(e.Key)
+		opts := setOptions{cost: e.Cost, ttl: e.Ttl, sliding: e.Sliding, deadline: e.Deadline}
+
+		if e.Ttl > 0 {
+			// an expiry date in the past would remove the key from the cache rather than skip the entry
+			if !e.ExpiryDate.After(c.now()) {
+				continue
+			}
+
+			opts.expiryDate = e.ExpiryDate
+		}
+
+		if err := c.set(e.Key, e.Value, opts); err != nil {
+			return fmt.Errorf("failed to load key %v: %w", e.Key, err)
+		}
+	}
+
+	return nil
+}
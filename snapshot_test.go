@@ -0,0 +1,243 @@
+package gocache
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCacheSnapshot(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := New(WithClock(clk), WithNegativeTtl(time.Minute))
+
+	c.Set("k1", "value1")
+	c.SetWithTtl("k2", 2, time.Hour)
+	c.SetWithTtl("k3", []byte("value3"), time.Minute)
+	c.SetWithSlidingTtl("k4", 4.5, time.Hour, 2*time.Hour)
+	c.SetWithTtl("expired", "value", time.Second)
+	c.cacheMiss("missing", ErrKeyNotFound)
+
+	clk.advance(30 * time.Second)
+
+	var buf bytes.Buffer
+	if err := c.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo - got: %v, want: nil", err)
+	}
+
+	snapshot := buf.Bytes()
+	saved := clk.Now()
+
+	// Test Case 1: The entries keep their TTL and their expiry date
+	t.Run("round trip", func(t *testing.T) {
+		loaded := New(WithClock(clk))
+		if err := loaded.LoadFrom(bytes.NewReader(snapshot)); err != nil {
+			t.Fatalf("LoadFrom - got: %v, want: nil", err)
+		}
+
+		if value, err := loaded.Get("k1"); err != nil || value != "value1" {
+			t.Errorf("Get k1 - got: %v, %v, want: value1, nil", value, err)
+		}
+
+		if value, err := loaded.Get("k3"); err != nil || string(value.([]byte)) != "value3" {
+			t.Errorf("Get k3 - got: %v, %v, want: value3, nil", value, err)
+		}
+
+		if ttl, left := loaded.GetTtl("k2"), loaded.TimeToLive("k2"); ttl != time.Hour || left != time.Hour-30*time.Second {
+			t.Errorf("k2 TTL, time left - got: %v, %v, want: 1h, 59m30s", ttl, left)
+		}
+
+		if left := loaded.TimeToLive("k1"); left != 0 {
+			t.Errorf("k1 time left - got: %v, want: 0", left)
+		}
+	})
+
+	// Test Case 2: Sliding entries keep sliding up to their maximum lifetime
+	t.Run("sliding", func(t *testing.T) {
+		loaded := New(WithClock(clk))
+		loaded.LoadFrom(bytes.NewReader(snapshot))
+
+		clk.advance(time.Hour)
+		loaded.Get("k4")
+		clk.advance(time.Hour)
+
+		if loaded.Has("k4") {
+			t.Errorf("has key k4 - got: true, want: false")
+		}
+	})
+
+	// Test Case 3: Expired entries and keys cached as not found are not saved
+	t.Run("not saved", func(t *testing.T) {
+		loaded := New(WithClock(&stubClock{now: saved}))
+		loaded.LoadFrom(bytes.NewReader(snapshot))
+
+		if loaded.Len() != 4 {
+			t.Errorf("Len - got: %d, want: 4", loaded.Len())
+		}
+	})
+
+	// Test Case 4: Entries expired since the snapshot was saved are skipped
+	t.Run("expired since", func(t *testing.T) {
+		later := &stubClock{now: saved.Add(time.Hour)}
+		loaded := New(WithClock(later))
+		loaded.Set("k3", "current")
+
+		if err := loaded.LoadFrom(bytes.NewReader(snapshot)); err != nil {
+			t.Fatalf("LoadFrom - got: %v, want: nil", err)
+		}
+
+		if value, err := loaded.Get("k3"); err != nil || value != "current" {
+			t.Errorf("Get k3 - got: %v, %v, want: current, nil", value, err)
+		}
+
+		if loaded.Has("k4") || !loaded.Has("k1") {
+			t.Errorf("has keys k4, k1 - got: %v, %v, want: false, true", loaded.Has("k4"), loaded.Has("k1"))
+		}
+	})
+
+	// Test Case 5: Loading stops at the first entry that cannot be set
+	t.Run("cache full", func(t *testing.T) {
+		loaded := New(WithClock(&stubClock{now: saved}), WithMaxKeys(1))
+
+		if err := loaded.LoadFrom(bytes.NewReader(snapshot)); !errors.Is(err, ErrCacheFull) {
+			t.Errorf("LoadFrom - got: %v, want: ErrCacheFull", err)
+		}
+	})
+}
+
+func TestCacheSnapshotErrors(t *testing.T) {
+	// Setup
+	c := New()
+	c.Set("k1", "value1")
+
+	var buf bytes.Buffer
+	c.SaveTo(&buf)
+
+	snapshot := buf.Bytes()
+
+	corrupt := func(f func(b []byte) []byte) []byte {
+		return f(append([]byte(nil), snapshot...))
+	}
+
+	testCases := []struct {
+		label    string
+		data     []byte
+		expected error
+	}{
+		{"empty", nil, ErrInvalidSnapshot},
+		{"not a snapshot", []byte("key=value\n"), ErrInvalidSnapshot},
+		{"truncated", snapshot[:len(snapshot)-1], ErrSnapshotChecksum},
+		{"unsupported version", corrupt(func(b []byte) []byte { b[len(snapshotMagic)+1] = snapshotVersion + 1; return b }), ErrSnapshotVersion},
+		{"version 0", corrupt(func(b []byte) []byte { b[len(snapshotMagic)+1] = 0; return b }), ErrSnapshotVersion},
+		{"corrupted", corrupt(func(b []byte) []byte { b[len(b)/2] ^= 0xff; return b }), ErrSnapshotChecksum},
+	}
+
+	// Test Case 1: Invalid snapshots are rejected and leave the cache as is
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			loaded := New()
+			loaded.Set("k0", "value0")
+
+			if err := loaded.LoadFrom(bytes.NewReader(tc.data)); !errors.Is(err, tc.expected) {
+				t.Errorf("LoadFrom - got: %v, want: %v", err, tc.expected)
+			}
+
+			if keys := loaded.Keys(); len(keys) != 1 || keys[0] != "k0" {
+				t.Errorf("Keys - got: %v, want: [k0]", keys)
+			}
+		})
+	}
+
+	// Test Case 2: Values of unregistered types cannot be saved
+	t.Run("unregistered type", func(t *testing.T) {
+		type point struct{ X, Y int }
+
+		c := New()
+		c.Set("k1", point{1, 2})
+
+		if err := c.SaveTo(&bytes.Buffer{}); err == nil {
+			t.Errorf("SaveTo - got: nil, want: error")
+		}
+	})
+}
+
+func TestTypedCacheSnapshot(t *testing.T) {
+	// Setup
+	type user struct {
+		Name  string
+		Roles []string
+	}
+
+	c := NewTyped[int, user]()
+	c.Set(1, user{Name: "alice", Roles: []string{"admin"}})
+	c.Set(2, user{Name: "bob"})
+
+	var buf bytes.Buffer
+	if err := c.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo - got: %v, want: nil", err)
+	}
+
+	// Test Case 1: Typed keys and values are saved without registration
+	t.Run("typed", func(t *testing.T) {
+		loaded := NewTyped[int, user]()
+		if err := loaded.LoadFrom(bytes.NewReader(buf.Bytes())); err != nil {
+			t.Fatalf("LoadFrom - got: %v, want: nil", err)
+		}
+
+		if value, err := loaded.Get(1); err != nil || value.Name != "alice" || len(value.Roles) != 1 {
+			t.Errorf("Get 1 - got: %+v, %v, want: alice, nil", value, err)
+		}
+	})
+
+	// Test Case 2: A snapshot of other types is rejected
+	t.Run("other types", func(t *testing.T) {
+		loaded := NewTyped[string, int]()
+
+		if err := loaded.LoadFrom(bytes.NewReader(buf.Bytes())); err == nil || loaded.Len() != 0 {
+			t.Errorf("LoadFrom - got: %v, %d keys, want: error, 0 keys", err, loaded.Len())
+		}
+	})
+}
+
+func TestShardedCacheSnapshot(t *testing.T) {
+	// Setup
+	sc := NewSharded(WithShards(4))
+
+	for _, key := range []string{"k1", "k2", "k3", "k4", "k5"} {
+		sc.SetWithTtl(key, key, time.Hour)
+	}
+
+	var buf bytes.Buffer
+	if err := sc.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo - got: %v, want: nil", err)
+	}
+
+	// Test Case 1: A snapshot is loaded into a cache with another number of shards
+	t.Run("resharded", func(t *testing.T) {
+		loaded := NewSharded(WithShards(7))
+		if err := loaded.LoadFrom(bytes.NewReader(buf.Bytes())); err != nil {
+			t.Fatalf("LoadFrom - got: %v, want: nil", err)
+		}
+
+		if loaded.Len() != 5 {
+			t.Errorf("Len - got: %d, want: 5", loaded.Len())
+		}
+
+		if value, err := loaded.Get("k3"); err != nil || value != "k3" {
+			t.Errorf("Get k3 - got: %v, %v, want: k3, nil", value, err)
+		}
+	})
+
+	// Test Case 2: A snapshot is loaded into a cache without shards
+	t.Run("unsharded", func(t *testing.T) {
+		loaded := NewSync()
+		if err := loaded.LoadFrom(bytes.NewReader(buf.Bytes())); err != nil {
+			t.Fatalf("LoadFrom - got: %v, want: nil", err)
+		}
+
+		if loaded.Len() != 5 {
+			t.Errorf("Len - got: %d, want: 5", loaded.Len())
+		}
+	})
+}
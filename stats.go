@@ -0,0 +1,156 @@
+package gocache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the statistics of a cache, since it was created or its statistics were reset.
+type Stats struct {
+	// Hits is the number of reads that found a value, with Get, GetAndDelete and the loading methods.
+	Hits int64
+	// Misses is the number of reads that did not find a value.
+	Misses int64
+	// Sets is the number of values set, including the loaded values.
+	Sets int64
+	// Deletes is the number of entries deleted, including by Clear.
+	Deletes int64
+	// Expirations is the number of expired entries removed from the cache.
+	Expirations int64
+	// Evictions is the number of entries evicted by the eviction policy.
+	Evictions int64
+	// Keys is the current number of entries in the cache, as returned by Len.
+	Keys int
+	// LoadSuccesses is the number of calls to a loader that succeeded.
+	LoadSuccesses int64
+	// LoadFailures is the number of calls to a loader that returned an error.
+	LoadFailures int64
+	// LoadTime is the total time spent in the calls to the loaders.
+	LoadTime time.Duration
+}
+
+// HitRatio returns the ratio of the reads that found a value, it is 0 if there were no reads.
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// AverageLoadTime returns the average time spent in a call to a loader, it is 0 if there were no calls.
+func (s Stats) AverageLoadTime() time.Duration {
+	loads := s.LoadSuccesses + s.LoadFailures
+	if loads == 0 {
+		return 0
+	}
+
+	return s.LoadTime / time.Duration(loads)
+}
+
+// add returns the sum of both statistics.
+func (s Stats) add(other Stats) Stats {
+	return Stats{
+		Hits:          s.Hits + other.Hits,
+		Misses:        s.Misses + other.Misses,
+		Sets:          s.Sets + other.Sets,
+		Deletes:       s.Deletes + other.Deletes,
+		Expirations:   s.Expirations + other.Expirations,
+		Evictions:     s.Evictions + other.Evictions,
+		Keys:          s.Keys + other.Keys,
+		LoadSuccesses: s.LoadSuccesses + other.LoadSuccesses,
+		LoadFailures:  s.LoadFailures + other.LoadFailures,
+		LoadTime:      s.LoadTime + other.LoadTime,
+	}
+}
+
+// statsCounters holds the counters of the statistics of a cache, updated atomically so that they can be
+// updated under the read lock of the cache, or without any lock.
+// It is allocated on its own to guarantee the 64-bit alignment of the counters required by the atomic
+// operations on 32-bit platforms.
+type statsCounters struct {
+	hits          int64
+	misses        int64
+	sets          int64
+	deletes       int64
+	expirations   int64
+	evictions     int64
+	loadSuccesses int64
+	loadFailures  int64
+	loadTime      int64
+}
+
+// read records a read that found a value or not.
+func (s *statsCounters) read(hit bool) {
+	if hit {
+		atomic.AddInt64(&s.hits, 1)
+	} else {
+		atomic.AddInt64(&s.misses, 1)
+	}
+}
+
+// set records a value set in the cache.
+func (s *statsCounters) set() {
+	atomic.AddInt64(&s.sets, 1)
+}
+
+// removed records the removal of an entry for the provided reason.
+func (s *statsCounters) removed(reason RemovalReason) {
+	switch reason {
+	case Deleted, Cleared:
+		atomic.AddInt64(&s.deletes, 1)
+	case Expired:
+		atomic.AddInt64(&s.expirations, 1)
+	case Evicted:
+		atomic.AddInt64(&s.evictions, 1)
+	}
+}
+
+// loaded records a call to a loader that took the provided duration, and whether it succeeded or not.
+func (s *statsCounters) loaded(d time.Duration, err error) {
+	if err == nil {
+		atomic.AddInt64(&s.loadSuccesses, 1)
+	} else {
+		atomic.AddInt64(&s.loadFailures, 1)
+	}
+
+	atomic.AddInt64(&s.loadTime, int64(d))
+}
+
+// snapshot returns the current value of the counters.
+func (s *statsCounters) snapshot() Stats {
+	return Stats{
+		Hits:          atomic.LoadInt64(&s.hits),
+		Misses:        atomic.LoadInt64(&s.misses),
+		Sets:          atomic.LoadInt64(&s.sets),
+		Deletes:       atomic.LoadInt64(&s.deletes),
+		Expirations:   atomic.LoadInt64(&s.expirations),
+		Evictions:     atomic.LoadInt64(&s.evictions),
+		LoadSuccesses: atomic.LoadInt64(&s.loadSuccesses),
+		LoadFailures:  atomic.LoadInt64(&s.loadFailures),
+		LoadTime:      time.Duration(atomic.LoadInt64(&s.loadTime)),
+	}
+}
+
+// reset sets all the counters to 0.
+func (s *statsCounters) reset() {
+	for _, counter := range []*int64{
+		&s.hits, &s.misses, &s.sets, &s.deletes, &s.expirations, &s.evictions,
+		&s.loadSuccesses, &s.loadFailures, &s.loadTime,
+	} {
+		atomic.StoreInt64(counter, 0)
+	}
+}
+
+// Stats returns a snapshot of the statistics of the cache.
+func (c *TypedCache[K, V]) Stats() Stats {
+	stats := c.stats.snapshot()
+	stats.Keys = c.size()
+
+	return stats
+}
+
+// ResetStats resets the statistics of the cache, except for the number of keys.
+func (c *TypedCache[K, V]) ResetStats() {
+	c.stats.reset()
+}
@@ -0,0 +1,216 @@
+package gocache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheStats(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := New(WithClock(clk), WithMaxKeys(3), WithEvictionPolicy(LRU))
+
+	// Test Case 1: Hits and misses
+	t.Run("hits and misses", func(t *testing.T) {
+		c.Set("k1", "value1")
+		c.Get("k1")
+		c.Get("k1")
+		c.Get("k2")
+
+		stats := c.Stats()
+
+		if stats.Hits != 2 || stats.Misses != 1 {
+			t.Errorf("hits, misses - got: %d, %d, want: 2, 1", stats.Hits, stats.Misses)
+		}
+
+		if ratio := stats.HitRatio(); ratio < 0.66 || ratio > 0.67 {
+			t.Errorf("HitRatio - got: %v, want: 0.67", ratio)
+		}
+	})
+
+	// Test Case 2: Sets and deletes
+	t.Run("sets and deletes", func(t *testing.T) {
+		c.Set("k1", "value2")
+		c.Set("k2", "value2")
+		c.Delete("k2")
+		c.GetAndDelete("k1")
+
+		stats := c.Stats()
+
+		if stats.Sets != 3 || stats.Deletes != 2 || stats.Keys != 0 {
+			t.Errorf("sets, deletes, keys - got: %d, %d, %d, want: 3, 2, 0", stats.Sets, stats.Deletes, stats.Keys)
+		}
+
+		if stats.Hits != 3 {
+			t.Errorf("hits - got: %d, want: 3", stats.Hits)
+		}
+	})
+
+	// Test Case 3: Expirations and evictions
+	t.Run("expirations and evictions", func(t *testing.T) {
+		c.SetWithTtl("k1", "value1", time.Second)
+		clk.advance(2 * time.Second)
+		c.PurgeExpired()
+
+		for i := 2; i <= 5; i++ {
+			c.Set(fmt.Sprintf("k%d", i), i)
+		}
+
+		stats := c.Stats()
+
+		if stats.Evictions != 1 || stats.Expirations != 1 {
+			t.Errorf("evictions, expirations - got: %d, %d, want: 1, 1", stats.Evictions, stats.Expirations)
+		}
+
+		if stats.Keys != 3 {
+			t.Errorf("keys - got: %d, want: 3", stats.Keys)
+		}
+	})
+
+	// Test Case 4: Clear counts as deletes
+	t.Run("clear", func(t *testing.T) {
+		deletes := c.Stats().Deletes
+		c.Clear()
+
+		if got := c.Stats().Deletes; got != deletes+3 {
+			t.Errorf("deletes - got: %d, want: %d", got, deletes+3)
+		}
+	})
+
+	// Test Case 5: Reset keeps the number of keys
+	t.Run("reset", func(t *testing.T) {
+		c.Set("k1", "value1")
+		c.ResetStats()
+
+		if stats := c.Stats(); stats != (Stats{Keys: 1}) {
+			t.Errorf("Stats - got: %+v, want: %+v", stats, Stats{Keys: 1})
+		}
+	})
+}
+
+func TestCacheLoadStats(t *testing.T) {
+	// Setup
+	c := NewSync()
+	ctx := context.Background()
+	errLoad := errors.New("load failed")
+
+	// Test Case 1: Successful loads
+	t.Run("successes", func(t *testing.T) {
+		loader := func(ctx context.Context) (any, time.Duration, error) {
+			time.Sleep(10 * time.Millisecond)
+
+			return "value1", 0, nil
+		}
+
+		c.GetOrLoad(ctx, "k1", loader)
+		c.GetOrLoad(ctx, "k1", loader)
+
+		stats := c.Stats()
+
+		if stats.LoadSuccesses != 1 || stats.LoadFailures != 0 {
+			t.Errorf("load successes, failures - got: %d, %d, want: 1, 0", stats.LoadSuccesses, stats.LoadFailures)
+		}
+
+		if stats.Hits != 1 || stats.Misses != 1 || stats.Sets != 1 {
+			t.Errorf("hits, misses, sets - got: %d, %d, %d, want: 1, 1, 1", stats.Hits, stats.Misses, stats.Sets)
+		}
+
+		if stats.AverageLoadTime() < 10*time.Millisecond {
+			t.Errorf("AverageLoadTime - got: %v, want: >= 10ms", stats.AverageLoadTime())
+		}
+	})
+
+	// Test Case 2: Failed loads
+	t.Run("failures", func(t *testing.T) {
+		c.GetOrLoad(ctx, "k2", func(ctx context.Context) (any, time.Duration, error) {
+			return nil, 0, errLoad
+		})
+
+		if stats := c.Stats(); stats.LoadFailures != 1 || stats.Misses != 2 {
+			t.Errorf("load failures, misses - got: %d, %d, want: 1, 2", stats.LoadFailures, stats.Misses)
+		}
+	})
+
+	// Test Case 3: Batch loads count as a single load
+	t.Run("batch", func(t *testing.T) {
+		c.GetManyOrLoad(ctx, []string{"k1", "k3", "k4"}, func(ctx context.Context, missing []string) (map[string]any, error) {
+			return map[string]any{"k3": "value3", "k4": "value4"}, nil
+		})
+
+		stats := c.Stats()
+
+		if stats.LoadSuccesses != 2 {
+			t.Errorf("load successes - got: %d, want: 2", stats.LoadSuccesses)
+		}
+
+		if stats.Hits != 2 || stats.Misses != 4 {
+			t.Errorf("hits, misses - got: %d, %d, want: 2, 4", stats.Hits, stats.Misses)
+		}
+	})
+}
+
+func TestShardedCacheStats(t *testing.T) {
+	// Setup
+	sc := NewSharded(WithShards(4))
+
+	for i := 0; i < 10; i++ {
+		sc.Set(fmt.Sprintf("k%d", i), i)
+		sc.Get(fmt.Sprintf("k%d", i))
+		sc.Get(fmt.Sprintf("missing%d", i))
+	}
+
+	sc.Delete("k0")
+
+	// Test Case 1: Statistics are summed across the shards
+	t.Run("summed", func(t *testing.T) {
+		want := Stats{Hits: 10, Misses: 10, Sets: 10, Deletes: 1, Keys: 9}
+
+		if stats := sc.Stats(); stats != want {
+			t.Errorf("Stats - got: %+v, want: %+v", stats, want)
+		}
+	})
+
+	// Test Case 2: Reset resets all the shards
+	t.Run("reset", func(t *testing.T) {
+		sc.ResetStats()
+
+		if stats := sc.Stats(); stats != (Stats{Keys: 9}) {
+			t.Errorf("Stats - got: %+v, want: %+v", stats, Stats{Keys: 9})
+		}
+	})
+}
+
+func TestSyncCacheStatsConcurrent(t *testing.T) {
+	// Setup
+	c := NewSync()
+
+	var wg sync.WaitGroup
+
+	// Test Case 1: Concurrent reads and writes are all counted
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				key := fmt.Sprintf("k%d-%d", i, j)
+				c.Set(key, j)
+				c.Get(key)
+				c.Get("missing")
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	want := Stats{Hits: 800, Misses: 800, Sets: 800, Keys: 800}
+
+	if stats := c.Stats(); stats != want {
+		t.Errorf("Stats - got: %+v, want: %+v", stats, want)
+	}
+}
@@ -0,0 +1,13 @@
+package gocache
+
+// SyncCache is an in-memory key-value store that is safe for concurrent use by multiple goroutines.
+// It exposes the same methods and configurations as [Cache], with every operation, including the
+// expiration of entries, guarded by a read/write lock.
+type SyncCache struct {
+	*Cache
+}
+
+// NewSync creates a new [SyncCache] instance with optional configurations and an empty data store.
+func NewSync(opts ...OptFunc) *SyncCache {
+	return &SyncCache{Cache: &Cache{TypedCache: NewTypedSync[string, any](opts...)}}
+}
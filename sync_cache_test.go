@@ -0,0 +1,213 @@
+package gocache
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	stressGoroutines = 16
+	stressIterations = 500
+)
+
+func TestSyncCacheConcurrentAccess(t *testing.T) {
+	// Setup
+	c := NewSync(WithMaxKeys(keyPoolSize))
+
+	keys := make([]string, keyPoolSize)
+	for i := 0; i < keyPoolSize; i++ {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	// Test Case 1: Concurrent reads and writes on the same keys
+	t.Run("concurrent reads and writes", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for g := 0; g < stressGoroutines; g++ {
+			wg.Add(1)
+
+			go func(g int) {
+				defer wg.Done()
+
+				for i := 0; i < stressIterations; i++ {
+					key := keys[(g*stressIterations+i)%keyPoolSize]
+
+					switch i % 8 {
+					case 0:
+						c.Set(key, i)
+					case 1:
+						c.SetWithTtl(key, i, time.Millisecond)
+					case 2:
+						c.Get(key)
+					case 3:
+						c.GetAndDelete(key)
+					case 4:
+						c.Delete(key)
+					case 5:
+						c.ChangeTtl(key, 2*time.Millisecond)
+					case 6:
+						c.GetTtl(key)
+						c.Has(key)
+					case 7:
+						c.Keys()
+					}
+				}
+			}(g)
+		}
+
+		wg.Wait()
+
+		if keyCount := len(c.Keys()); keyCount > keyPoolSize {
+			t.Errorf("keys length - got: %d, want: at most %d", keyCount, keyPoolSize)
+		}
+	})
+
+	// Test Case 2: Concurrent writes while clearing the cache
+	t.Run("concurrent writes and clear", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for g := 0; g < stressGoroutines; g++ {
+			wg.Add(1)
+
+			go func(g int) {
+				defer wg.Done()
+
+				for i := 0; i < stressIterations; i++ {
+					if g == 0 && i%50 == 0 {
+						c.Clear()
+						continue
+					}
+
+					c.SetWithTtl(keys[(g+i)%keyPoolSize], i, time.Duration(i%3)*time.Millisecond)
+				}
+			}(g)
+		}
+
+		wg.Wait()
+	})
+}
+
+func TestSyncCacheConcurrentClear(t *testing.T) {
+	// Setup
+	c := NewSync(WithMaxKeys(10), WithEvictionPolicy(LRU))
+
+	// Test Case 1: Concurrent reads while a cache with an eviction policy is filled and cleared
+	t.Run("concurrent reads and clear with eviction policy", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for g := 0; g < stressGoroutines; g++ {
+			wg.Add(1)
+
+			go func(g int) {
+				defer wg.Done()
+
+				for i := 0; i < stressIterations; i++ {
+					key := strconv.Itoa((g + i) % keyPoolSize)
+
+					if g == 0 {
+						c.Set(key, i)
+						c.Clear()
+					} else {
+						c.Get(key)
+					}
+				}
+			}(g)
+		}
+
+		wg.Wait()
+
+		if keyCount := c.Len(); keyCount != 0 {
+			t.Errorf("keys length - got: %d, want: 0", keyCount)
+		}
+	})
+}
+
+func TestSyncCacheExpiry(t *testing.T) {
+	// Setup
+	c := NewSync()
+
+	// Test Case 1: Expiry callbacks racing with readers
+	t.Run("expiry while reading", func(t *testing.T) {
+		for i := 0; i < keyPoolSize; i++ {
+			c.SetWithTtl(fmt.Sprintf("k%d", i), i, time.Duration(i%5+1)*time.Millisecond)
+		}
+
+		var wg sync.WaitGroup
+
+		for g := 0; g < stressGoroutines; g++ {
+			wg.Add(1)
+
+			go func(g int) {
+				defer wg.Done()
+
+				for i := 0; i < stressIterations; i++ {
+					c.Get(fmt.Sprintf("k%d", (g+i)%keyPoolSize))
+				}
+			}(g)
+		}
+
+		wg.Wait()
+
+		time.Sleep(50 * time.Millisecond)
+
+		if keys := c.Keys(); len(keys) != 0 {
+			t.Errorf("keys length - got: %d, want: 0", len(keys))
+		}
+	})
+
+	// Test Case 2: Replaced entry is not removed by the old entry's expiry
+	t.Run("replaced entry survives old expiry", func(t *testing.T) {
+		for i := 0; i < stressIterations; i++ {
+			c.SetWithTtl("k", i, time.Millisecond)
+			c.Set("k", "permanent")
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		if value, err := c.Get("k"); err != nil || value != "permanent" {
+			t.Errorf("Get k - got: %v, %v, want: permanent, nil", value, err)
+		}
+	})
+}
+
+func BenchmarkSyncCacheGetParallel(b *testing.B) {
+	c := NewSync()
+
+	keys := make([]string, keyPoolSize)
+	for i := 0; i < keyPoolSize; i++ {
+		keys[i] = strconv.Itoa(i)
+		c.Set(keys[i], fmt.Sprintf("value%d", i))
+	}
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			c.Get(keys[i%keyPoolSize])
+			i++
+		}
+	})
+}
+
+func BenchmarkSyncCacheSetParallel(b *testing.B) {
+	c := NewSync()
+
+	keys := make([]string, keyPoolSize)
+	for i := 0; i < keyPoolSize; i++ {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			c.Set(keys[i%keyPoolSize], i)
+			i++
+		}
+	})
+}
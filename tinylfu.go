@@ -0,0 +1,201 @@
+package gocache
+
+import "container/list"
+
+const (
+	// tinyLFUWindowRatio is the share of the capacity given to the admission window.
+	tinyLFUWindowRatio = 0.01
+	// tinyLFUProtectedRatio is the share of the main space given to the protected segment.
+	tinyLFUProtectedRatio = 0.8
+)
+
+// tinyLFUSegment identifies the list a key of a [tinyLFUPolicy] is in.
+type tinyLFUSegment int
+
+const (
+	windowSegment tinyLFUSegment = iota
+	probationSegment
+	protectedSegment
+)
+
+// tinyLFUNode is a key of a [tinyLFUPolicy] along with the segment it is in.
+type tinyLFUNode struct {
+	key     any
+	segment tinyLFUSegment
+}
+
+// tinyLFUPolicy is an [EvictionPolicy] implementing Window-TinyLFU.
+// New keys enter a small admission window LRU. When the cache is full, the key leaving the window
+// competes with the victim of the main space, a segmented LRU, and only the one with the highest
+// estimated frequency is kept. The frequencies are estimated by a count-min sketch that is aged
+// periodically, and are remembered after a key is evicted.
+// The main space is split between a probation segment, where keys coming from the window land, and
+// a protected segment, where keys are promoted when accessed again while on probation.
+type tinyLFUPolicy struct {
+	sketch *countMinSketch
+
+	window    *list.List
+	probation *list.List
+	protected *list.List
+	elements  map[any]*list.Element
+
+	windowCap    int
+	protectedCap int
+}
+
+// WTinyLFU creates an [EvictionPolicy] implementing Window-TinyLFU, which keeps a high hit ratio on
+// both recency and frequency skewed workloads and resists scans: a new key only displaces an
+// existing entry when it is estimated to be used more frequently.
+func WTinyLFU() EvictionPolicy {
+	p := &tinyLFUPolicy{
+		window:    list.New(),
+		probation: list.New(),
+		protected: list.New(),
+		elements:  make(map[any]*list.Element),
+	}
+	p.setCapacity(0)
+
+	return p
+}
+
+// setCapacity sizes the window, the protected segment and the sketch for the provided number of keys.
+// A capacity less than 1 means unlimited, in which case the policy is sized for a small cache.
+func (p *tinyLFUPolicy) setCapacity(capacity int) {
+	if capacity < 1 {
+		capacity = 100
+	}
+
+	p.windowCap = int(float64(capacity) * tinyLFUWindowRatio)
+	if p.windowCap < 1 {
+		p.windowCap = 1
+	}
+
+	p.protectedCap = int(float64(capacity-p.windowCap) * tinyLFUProtectedRatio)
+	p.sketch = newCountMinSketch(capacity)
+}
+
+// OnInsert records the use of the key and adds it to the admission window.
+func (p *tinyLFUPolicy) OnInsert(key any) {
+	p.sketch.increment(key)
+
+	if _, ok := p.elements[key]; ok {
+		p.touch(key)
+		return
+	}
+
+	p.elements[key] = p.window.PushFront(&tinyLFUNode{key: key, segment: windowSegment})
+
+	// while the cache is not full, keys leaving the window are admitted in the main space directly
+	for p.window.Len() > p.windowCap {
+		p.move(p.window.Back(), p.probation, probationSegment)
+	}
+}
+
+// OnAccess records the use of the key and promotes it.
+func (p *tinyLFUPolicy) OnAccess(key any) {
+	p.sketch.increment(key)
+	p.touch(key)
+}
+
+// OnUpdate records the use of the key and promotes it.
+func (p *tinyLFUPolicy) OnUpdate(key any) {
+	p.OnAccess(key)
+}
+
+// OnRemove stops tracking the key, its estimated frequency is kept.
+func (p *tinyLFUPolicy) OnRemove(key any) {
+	elem, ok := p.elements[key]
+
+	if !ok {
+		return
+	}
+
+	p.list(elem.Value.(*tinyLFUNode).segment).Remove(elem)
+	delete(p.elements, key)
+}
+
+// Victim makes the key leaving the admission window compete with the victim of the main space, and
+// returns the one with the lowest estimated frequency. The winner, if coming from the window, is
+// admitted on probation in the main space.
+func (p *tinyLFUPolicy) Victim() (any, bool) {
+	var candidate, victim *list.Element
+
+	if p.window.Len() >= p.windowCap {
+		candidate = p.window.Back()
+	}
+
+	if victim = p.probation.Back(); victim == nil {
+		victim = p.protected.Back()
+	}
+
+	if candidate == nil {
+		candidate, victim = victim, nil
+	}
+
+	if candidate == nil {
+		if candidate = p.window.Back(); candidate == nil {
+			return nil, false
+		}
+	}
+
+	evicted := candidate
+
+	if victim != nil {
+		candidateKey := candidate.Value.(*tinyLFUNode).key
+		victimKey := victim.Value.(*tinyLFUNode).key
+
+		if p.sketch.estimate(candidateKey) > p.sketch.estimate(victimKey) {
+			p.move(candidate, p.probation, probationSegment)
+			evicted = victim
+		}
+	}
+
+	key := evicted.Value.(*tinyLFUNode).key
+	p.OnRemove(key)
+
+	return key, true
+}
+
+// touch moves the key to the front of its segment, promoting it to the protected segment if it was
+// on probation. Keys overflowing the protected segment are demoted back on probation.
+func (p *tinyLFUPolicy) touch(key any) {
+	elem, ok := p.elements[key]
+
+	if !ok {
+		return
+	}
+
+	switch elem.Value.(*tinyLFUNode).segment {
+	case windowSegment:
+		p.window.MoveToFront(elem)
+	case protectedSegment:
+		p.protected.MoveToFront(elem)
+	case probationSegment:
+		p.move(elem, p.protected, protectedSegment)
+
+		for p.protected.Len() > p.protectedCap {
+			p.move(p.protected.Back(), p.probation, probationSegment)
+		}
+	}
+}
+
+// move moves the element to the front of the list of the provided segment.
+func (p *tinyLFUPolicy) move(elem *list.Element, to *list.List, segment tinyLFUSegment) {
+	node := elem.Value.(*tinyLFUNode)
+
+	p.list(node.segment).Remove(elem)
+	node.segment = segment
+	p.elements[node.key] = to.PushFront(node)
+}
+
+// list returns the list of the provided segment.
+func (p *tinyLFUPolicy) list(segment tinyLFUSegment) *list.List {
+	switch segment {
+	case probationSegment:
+		return p.probation
+	case protectedSegment:
+		return p.protected
+	default:
+		return p.window
+	}
+}
@@ -0,0 +1,210 @@
+package gocache
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+const hitRatioCapacity = 500
+
+func TestCountMinSketch(t *testing.T) {
+	s := newCountMinSketch(64)
+
+	// Test Case 1: Estimate frequencies
+	t.Run("estimate", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			s.increment("hot")
+		}
+
+		s.increment("cold")
+
+		if freq := s.estimate("hot"); freq != 10 {
+			t.Errorf("estimate hot - got: %d, want: 10", freq)
+		}
+
+		if freq := s.estimate("cold"); freq != 1 {
+			t.Errorf("estimate cold - got: %d, want: 1", freq)
+		}
+
+		if freq := s.estimate("none"); freq > 1 {
+			t.Errorf("estimate none - got: %d, want: at most 1", freq)
+		}
+	})
+
+	// Test Case 2: Counters are capped
+	t.Run("counters capped", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			s.increment("hot")
+		}
+
+		if freq := s.estimate("hot"); freq != 15 {
+			t.Errorf("estimate hot - got: %d, want: 15", freq)
+		}
+	})
+
+	// Test Case 3: Counters are halved once the sample size is reached
+	t.Run("aging", func(t *testing.T) {
+		for i := 0; s.additions != 0 && s.additions < s.sampleSize-1; i++ {
+			s.increment(i)
+		}
+
+		s.increment(-1)
+
+		if freq := s.estimate("hot"); freq != 7 {
+			t.Errorf("estimate hot after aging - got: %d, want: 7", freq)
+		}
+	})
+}
+
+func TestHashKey(t *testing.T) {
+	type point struct{ x, y int }
+
+	if hashKey("k1") != hashKey("k1") || hashKey("k1") == hashKey("k2") {
+		t.Error("hashKey of strings - want: equal for equal keys, different otherwise")
+	}
+
+	if hashKey(1) != hashKey(1) || hashKey(1) == hashKey(2) {
+		t.Error("hashKey of ints - want: equal for equal keys, different otherwise")
+	}
+
+	if hashKey(point{1, 2}) != hashKey(point{1, 2}) || hashKey(point{1, 2}) == hashKey(point{2, 1}) {
+		t.Error("hashKey of structs - want: equal for equal keys, different otherwise")
+	}
+}
+
+func TestTinyLFUPolicyAdmission(t *testing.T) {
+	p := WTinyLFU()
+	p.(*tinyLFUPolicy).setCapacity(3)
+
+	// fill the cache: k1 is in the window, k2 and k3 in the main space
+	p.OnInsert("k3")
+	p.OnInsert("k2")
+	p.OnInsert("k1")
+
+	// k2 becomes popular, then is demoted on probation by k3
+	for i := 0; i < 5; i++ {
+		p.OnAccess("k2")
+	}
+
+	// Test Case 1: Unpopular window key is rejected
+	t.Run("reject candidate", func(t *testing.T) {
+		p.OnAccess("k3")
+
+		if key, ok := p.Victim(); !ok || key != "k1" {
+			t.Errorf("Victim - got: %v, %v, want: k1, true", key, ok)
+		}
+
+		p.OnInsert("k4")
+	})
+
+	// Test Case 2: Popular window key is admitted
+	t.Run("admit candidate", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			p.OnAccess("k4")
+		}
+
+		if key, ok := p.Victim(); !ok || key != "k2" {
+			t.Errorf("Victim - got: %v, %v, want: k2, true", key, ok)
+		}
+	})
+
+	// Test Case 3: Evicted keys keep their frequency
+	t.Run("frequency kept", func(t *testing.T) {
+		if freq := p.(*tinyLFUPolicy).sketch.estimate("k2"); freq != 6 {
+			t.Errorf("estimate k2 - got: %d, want: 6", freq)
+		}
+	})
+}
+
+// hitRatio replays the trace of keys on a cache, setting the keys on misses, and returns the ratio of hits.
+func hitRatio(newPolicy func() EvictionPolicy, trace []string) float64 {
+	c := New(WithMaxKeys(hitRatioCapacity), WithEvictionPolicy(newPolicy))
+	hits := 0
+
+	for _, key := range trace {
+		if _, err := c.Get(key); err == nil {
+			hits++
+			continue
+		}
+
+		c.Set(key, key)
+	}
+
+	return float64(hits) / float64(len(trace))
+}
+
+// zipfTrace returns a trace of keys following a Zipf distribution.
+func zipfTrace(n int) []string {
+	rnd := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(rnd, 1.01, 1, 100000)
+
+	trace := make([]string, n)
+	for i := range trace {
+		trace[i] = strconv.FormatUint(zipf.Uint64(), 10)
+	}
+
+	return trace
+}
+
+// scanTrace returns a trace of a hot set of keys regularly interrupted by scans of cold keys that
+// are never used again.
+func scanTrace(n int) []string {
+	rnd := rand.New(rand.NewSource(1))
+	trace := make([]string, 0, n)
+	cold := 0
+
+	for len(trace) < n {
+		for i := 0; i < hitRatioCapacity && len(trace) < n; i++ {
+			trace = append(trace, "hot"+strconv.Itoa(rnd.Intn(hitRatioCapacity/2)))
+		}
+
+		for i := 0; i < 2*hitRatioCapacity && len(trace) < n; i++ {
+			trace = append(trace, "cold"+strconv.Itoa(cold))
+			cold++
+		}
+	}
+
+	return trace
+}
+
+func TestTinyLFUHitRatio(t *testing.T) {
+	// Test Case 1: Frequency skewed workload
+	t.Run("zipf", func(t *testing.T) {
+		trace := zipfTrace(200000)
+		lru, tinyLFU := hitRatio(LRU, trace), hitRatio(WTinyLFU, trace)
+
+		t.Logf("hit ratio - lru: %.3f, w-tinylfu: %.3f", lru, tinyLFU)
+
+		if tinyLFU <= lru {
+			t.Errorf("hit ratio - got: %.3f, want: more than LRU's %.3f", tinyLFU, lru)
+		}
+	})
+
+	// Test Case 2: Scans of cold keys
+	t.Run("scan", func(t *testing.T) {
+		trace := scanTrace(200000)
+		lru, tinyLFU := hitRatio(LRU, trace), hitRatio(WTinyLFU, trace)
+
+		t.Logf("hit ratio - lru: %.3f, w-tinylfu: %.3f", lru, tinyLFU)
+
+		if tinyLFU < lru+0.1 {
+			t.Errorf("hit ratio - got: %.3f, want: at least LRU's %.3f + 0.1", tinyLFU, lru)
+		}
+	})
+}
+
+func BenchmarkCacheSetWTinyLFU(b *testing.B) {
+	c := New(WithMaxKeys(keyPoolSize/2), WithEvictionPolicy(WTinyLFU))
+
+	keys := make([]string, keyPoolSize)
+	for i := 0; i < keyPoolSize; i++ {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Set(keys[i%keyPoolSize], i)
+	}
+}
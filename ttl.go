@@ -0,0 +1,171 @@
+package gocache
+
+import (
+	"context"
+	"time"
+)
+
+// SetWithExpiry sets a key-value pair in the cache that expires at the provided date.
+// An expiry date that is not in the future expires the key instead, which removes it from the cache
+// unless the entries are not deleted on expiry, see [WithDeleteOnExpire].
+// If an error occurs, it will be returned, otherwise nil will be returned.
+func (c *TypedCache[K, V]) SetWithExpiry(key K, value V, expiryDate time.Time) error {
+	return c.store(key, value, setOptions{expiryDate: expiryDate.UTC()})
+}
+
+// ExpireAt changes the expiry date of the provided key in the cache, its TTL becoming the time left
+// until that date and no longer sliding.
+// An expiry date that is not in the future expires the key, which removes it from the cache unless the
+// entries are not deleted on expiry, see [WithDeleteOnExpire].
+// It returns a bool indicating whether a change in expiry has occurred or not.
+func (c *TypedCache[K, V]) ExpireAt(key K, expiryDate time.Time) bool {
+	obs := c.observeKey(context.Background(), OpExpireAt, key)
+	ok := c.expireAt(key, expiryDate)
+	obs.done(foundOutcome(ok))
+
+	return ok
+}
+
+// expireAt is ExpireAt, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) expireAt(key K, expiryDate time.Time) bool {
+	c.lock()
+	defer c.unlock()
+
+	now := c.now()
+	val, ok := c.data[key]
+
+	if !ok || !val.found(now) {
+		return false
+	}
+
+	if !expiryDate.After(now) {
+		c.expire(key, val, expiryDate, now)
+
+		return true
+	}
+
+	val.ttl = expiryDate.Sub(now)
+	val.sliding = false
+	val.expiryDate = expiryDate.UTC()
+
+	c.reschedule(val)
+
+	return true
+}
+
+// expire expires the provided entry at the provided date, which is not in the future. The entry is removed
+// from the cache, unless the entries are not deleted on expiry, in which case it is kept, flagged as expired,
+// until purged like the entries that expired on their own.
+func (c *TypedCache[K, V]) expire(key K, val *cacheValue[K, V], expiryDate time.Time, now time.Time) {
+	if c.deleteOnExpire {
+		c.remove(key, val, Expired)
+
+		return
+	}
+
+	// an entry is expired once past its expiry date
+	if !expiryDate.Before(now) {
+		expiryDate = now.Add(-time.Nanosecond)
+	}
+
+	// the TTL of an expired entry only flags that it expires, the TTL it was set with is kept if any
+	if val.ttl <= 0 {
+		val.ttl = now.Sub(expiryDate)
+	}
+
+	val.sliding = false
+	val.expiryDate = expiryDate.UTC()
+
+	c.reschedule(val)
+}
+
+// Persist removes the TTL of the provided key in the cache, so that it never expires.
+// It returns a bool indicating whether the key had a TTL that was removed or not.
+func (c *TypedCache[K, V]) Persist(key K) bool {
+	obs := c.observeKey(context.Background(), OpPersist, key)
+	ok := c.persist(key)
+	obs.done(foundOutcome(ok))
+
+	return ok
+}
+
+// persist is Persist, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) persist(key K) bool {
+	c.lock()
+	defer c.unlock()
+
+	val, ok := c.data[key]
+
+	if !ok || val.ttl <= 0 || !val.found(c.now()) {
+		return false
+	}
+
+	val.ttl = 0
+	val.sliding = false
+	val.deadline = time.Time{}
+
+	c.reschedule(val)
+
+	return true
+}
+
+// Touch restarts the TTL of the provided key in the cache, pushing its expiry date to its TTL from now.
+// The expiry date of an entry with a sliding TTL is not pushed past its maximum lifetime.
+// It returns a bool indicating whether the key exists or not.
+func (c *TypedCache[K, V]) Touch(key K) bool {
+	obs := c.observeKey(context.Background(), OpTouch, key)
+	ok := c.touch(key)
+	obs.done(foundOutcome(ok))
+
+	return ok
+}
+
+// touch is Touch, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) touch(key K) bool {
+	c.lock()
+	defer c.unlock()
+
+	now := c.now()
+	val, ok := c.data[key]
+
+	if !ok || !val.found(now) {
+		return false
+	}
+
+	if val.ttl > 0 {
+		val.expiryDate = val.expiryFrom(now)
+
+		c.reschedule(val)
+	}
+
+	return true
+}
+
+// TimeToLive returns the time left, as a duration, before the provided key in the cache expires.
+// It returns 0 if the key never expires, and -1 if the key does not exist.
+func (c *TypedCache[K, V]) TimeToLive(key K) time.Duration {
+	obs := c.observeKey(context.Background(), OpTimeToLive, key)
+	ttl := c.timeToLive(key)
+	obs.done(foundOutcome(ttl != -1))
+
+	return ttl
+}
+
+// timeToLive is TimeToLive, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) timeToLive(key K) time.Duration {
+	c.rLock()
+	defer c.rUnlock()
+
+	now := c.now()
+	val, ok := c.data[key]
+
+	if !ok || !val.found(now) {
+		return -1
+	}
+
+	if val.ttl <= 0 {
+		return 0
+	}
+
+	return val.expiryDate.Sub(now)
+}
@@ -0,0 +1,230 @@
+package gocache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCacheSetWithExpiry(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := New(WithClock(clk))
+
+	// Test Case 1: Entry expires at the provided date
+	t.Run("future date", func(t *testing.T) {
+		if err := c.SetWithExpiry("k1", "value1", clk.now.Add(time.Hour)); err != nil {
+			t.Errorf("SetWithExpiry k1: err - got: %v, want: nil", err)
+		}
+
+		if ttl := c.TimeToLive("k1"); ttl != time.Hour {
+			t.Errorf("TimeToLive k1 - got: %v, want: 1h", ttl)
+		}
+
+		clk.advance(time.Hour + time.Second)
+
+		if _, err := c.Get("k1"); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("Get k1: err - got: %v, want: ErrKeyNotFound", err)
+		}
+	})
+
+	// Test Case 2: Past date removes the key
+	t.Run("past date", func(t *testing.T) {
+		c.Set("k2", "value2")
+
+		if err := c.SetWithExpiry("k2", "new value2", clk.now.Add(-time.Second)); err != nil {
+			t.Errorf("SetWithExpiry k2: err - got: %v, want: nil", err)
+		}
+
+		if _, ok := c.data["k2"]; ok {
+			t.Errorf("k2 in store - got: true, want: false")
+		}
+	})
+}
+
+func TestCacheExpireAt(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := New(WithClock(clk))
+	c.Set("k1", "value1")
+	c.SetWithSlidingTtl("k2", "value2", time.Minute, 0)
+	c.Set("k3", "value3")
+
+	// Test Case 1: Sets an absolute expiry date
+	t.Run("future date", func(t *testing.T) {
+		if !c.ExpireAt("k1", clk.now.Add(time.Hour)) {
+			t.Errorf("ExpireAt k1 - got: false, want: true")
+		}
+
+		if ttl := c.TimeToLive("k1"); ttl != time.Hour {
+			t.Errorf("TimeToLive k1 - got: %v, want: 1h", ttl)
+		}
+
+		if c.data["k1"].index < 0 {
+			t.Errorf("k1 scheduled - got: false, want: true")
+		}
+	})
+
+	// Test Case 2: Sliding entries stop sliding
+	t.Run("sliding", func(t *testing.T) {
+		c.ExpireAt("k2", clk.now.Add(2*time.Minute))
+		clk.advance(time.Minute)
+		c.Get("k2")
+
+		if ttl := c.TimeToLive("k2"); ttl != time.Minute {
+			t.Errorf("TimeToLive k2 - got: %v, want: 1m", ttl)
+		}
+	})
+
+	// Test Case 3: Past date removes the key
+	t.Run("past date", func(t *testing.T) {
+		if !c.ExpireAt("k3", clk.now) {
+			t.Errorf("ExpireAt k3 - got: false, want: true")
+		}
+
+		if c.Has("k3") || c.Len() != 2 {
+			t.Errorf("has key k3 - got: true, want: false")
+		}
+	})
+
+	// Test Case 4: Missing key
+	t.Run("missing key", func(t *testing.T) {
+		if c.ExpireAt("k4", clk.now.Add(time.Hour)) {
+			t.Errorf("ExpireAt k4 - got: true, want: false")
+		}
+	})
+}
+
+func TestCacheExpireAtNotDeleted(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := New(WithClock(clk), WithDeleteOnExpire(false))
+	c.Set("k1", "value1")
+	c.SetWithTtl("k2", "value2", time.Hour)
+
+	// Test Case 1: Past date expires the key, which stays in the store like the keys expired on their own
+	t.Run("expire at past date", func(t *testing.T) {
+		if !c.ExpireAt("k1", clk.now) {
+			t.Errorf("ExpireAt k1 - got: false, want: true")
+		}
+
+		if c.Has("k1") || c.Len() != 2 || c.LiveLen() != 1 {
+			t.Errorf("Has k1, Len, LiveLen - got: %v, %d, %d, want: false, 2, 1", c.Has("k1"), c.Len(), c.LiveLen())
+		}
+	})
+
+	// Test Case 2: Setting with a past date expires the key
+	t.Run("set with past date", func(t *testing.T) {
+		if err := c.SetWithExpiry("k2", "new value2", clk.now.Add(-time.Second)); err != nil {
+			t.Errorf("SetWithExpiry k2: err - got: %v, want: nil", err)
+		}
+
+		if c.Has("k2") || c.Len() != 2 || c.LiveLen() != 0 {
+			t.Errorf("Has k2, Len, LiveLen - got: %v, %d, %d, want: false, 2, 0", c.Has("k2"), c.Len(), c.LiveLen())
+		}
+	})
+
+	// Test Case 3: Expired keys are purged
+	t.Run("purged", func(t *testing.T) {
+		if count := c.PurgeExpired(); count != 2 || c.Len() != 0 {
+			t.Errorf("PurgeExpired, Len - got: %d, %d, want: 2, 0", count, c.Len())
+		}
+	})
+}
+
+func TestCachePersist(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := New(WithClock(clk), WithStdTtl(time.Minute))
+	c.Set("k1", "value1")
+	c.SetWithTtl("k2", "value2", 0)
+
+	// Test Case 1: Removes the TTL
+	t.Run("with ttl", func(t *testing.T) {
+		if !c.Persist("k1") {
+			t.Errorf("Persist k1 - got: false, want: true")
+		}
+
+		if c.data["k1"].index >= 0 {
+			t.Errorf("k1 scheduled - got: true, want: false")
+		}
+
+		clk.advance(time.Hour)
+
+		if ttl := c.TimeToLive("k1"); ttl != 0 {
+			t.Errorf("TimeToLive k1 - got: %v, want: 0", ttl)
+		}
+	})
+
+	// Test Case 2: Key without a TTL
+	t.Run("without ttl", func(t *testing.T) {
+		if c.Persist("k2") {
+			t.Errorf("Persist k2 - got: true, want: false")
+		}
+	})
+
+	// Test Case 3: Missing key
+	t.Run("missing key", func(t *testing.T) {
+		if c.Persist("k3") {
+			t.Errorf("Persist k3 - got: true, want: false")
+		}
+	})
+}
+
+func TestCacheTouch(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := New(WithClock(clk), WithDeleteOnExpire(false))
+	c.SetWithTtl("k1", "value1", time.Minute)
+	c.SetWithSlidingTtl("k2", "value2", time.Minute, 90*time.Second)
+
+	// Test Case 1: Restarts the TTL
+	t.Run("restart", func(t *testing.T) {
+		clk.advance(50 * time.Second)
+
+		if !c.Touch("k1") || !c.Touch("k2") {
+			t.Errorf("Touch - got: false, want: true")
+		}
+
+		if ttl := c.TimeToLive("k1"); ttl != time.Minute {
+			t.Errorf("TimeToLive k1 - got: %v, want: 1m", ttl)
+		}
+
+		if ttl := c.TimeToLive("k2"); ttl != 40*time.Second {
+			t.Errorf("TimeToLive k2 - got: %v, want: 40s", ttl)
+		}
+	})
+
+	// Test Case 2: Expired keys are not touched
+	t.Run("expired", func(t *testing.T) {
+		clk.advance(time.Minute + time.Second)
+
+		if c.Touch("k1") {
+			t.Errorf("Touch k1 - got: true, want: false")
+		}
+
+		if ttl := c.TimeToLive("k1"); ttl != -1 {
+			t.Errorf("TimeToLive k1 - got: %v, want: -1", ttl)
+		}
+
+		if c.Len() != 2 {
+			t.Errorf("Len - got: %d, want: 2", c.Len())
+		}
+	})
+}
+
+func TestSyncCacheExpireAt(t *testing.T) {
+	c := NewSync(WithExpiryResolution(time.Millisecond))
+	c.Set("k1", "value1")
+
+	// Test Case 1: Deleted at the expiry date
+	t.Run("deleted on expiry", func(t *testing.T) {
+		c.ExpireAt("k1", time.Now().Add(50*time.Millisecond))
+
+		time.Sleep(100 * time.Millisecond)
+
+		if c.Len() != 0 {
+			t.Errorf("Len - got: %d, want: 0", c.Len())
+		}
+	})
+}
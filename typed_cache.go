@@ -0,0 +1,841 @@
+package gocache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TypedCache is a generic in-memory key-value store, with keys of type K and values of type V.
+// It accepts the same configurations as [Cache], which is a TypedCache with string keys and values of any type.
+//
+// A TypedCache is not safe for concurrent use unless created with [NewTypedSync]. The expiry and cleanup
+// timers of a TypedCache that is not safe for concurrent use do not modify it from their own goroutines,
+// the entries due are deleted by the next operation on the cache.
+type TypedCache[K comparable, V any] struct {
+	config
+
+	// mu guards the data store when the cache is used concurrently.
+	// It is nil for a non-concurrent cache, in which case no locking occurs.
+	mu *sync.RWMutex
+	// keyCount is the number of keys shared between the shards of a [ShardedCache], used to enforce
+	// the maximum number of keys across all of them, unless they have an eviction policy.
+	// It is nil for a standalone cache, in which case the size of the data store is used.
+	keyCount *int64
+	// sharedCost is the total cost of the entries shared between the shards of a [ShardedCache], used to
+	// enforce the maximum cost across all of them.
+	// It is nil for a standalone cache, in which case cost is used.
+	sharedCost *int64
+	// policy selects the entries to evict when the cache is full.
+	// It is nil when no eviction policy is set, in which case new keys are rejected when the cache is full.
+	policy EvictionPolicy
+	// cost is the total cost of the entries stored in the cache.
+	cost int64
+	// negatives is the number of keys cached as not found, which are dropped to make room for the values.
+	negatives int
+	// expiries holds the entries to delete on expiry, ordered by expiry date.
+	expiries expiryHeap[K, V]
+	// expiryTimer fires when the earliest entries in expiries are due.
+	expiryTimer Timer
+	// expiryAt is the time expiryTimer is set to fire at, it is zero if the timer is not set.
+	expiryAt time.Time
+	// cleanupTimer fires when the expired entries are to be purged, if not deleted on expiry.
+	cleanupTimer Timer
+	// cleanupArmed defines whether cleanupTimer is set to fire.
+	cleanupArmed bool
+	// expiryDue and cleanupDue are set when expiryTimer and cleanupTimer fire on a non-concurrent cache,
+	// for the next operation on the cache to delete the expired entries, see onTimer.
+	expiryDue  int32
+	cleanupDue int32
+	// removals holds the entries removed while the write lock is held, to notify once it is released.
+	removals []removal[K, V]
+	// subscribers receive the changes made to the cache, the list is replaced rather than modified.
+	subscribers []*subscriber[K, V]
+	// events holds the changes made while the write lock is held, to send to the subscribers once it is released.
+	events []TypedEvent[K, V]
+	// loadMu guards the loads in progress, whether or not the cache is used concurrently.
+	loadMu sync.Mutex
+	// flights holds the loads in progress by key, shared by the callers loading the same key.
+	flights map[K]*flight[V]
+	// batcher groups the keys loaded by GetManyOrLoad, it is shared between the shards of a [ShardedCache].
+	batcher *batcher[K, V]
+	// stats holds the counters of the statistics of the cache.
+	stats *statsCounters
+	// rnd draws the early expirations and the TTL jitter, it is nil if both are disabled.
+	rnd *lockedRand
+
+	data map[K]*cacheValue[K, V]
+}
+
+// NewTyped creates a new [TypedCache] instance with optional configurations and an empty data store.
+func NewTyped[K comparable, V any](opts ...OptFunc) *TypedCache[K, V] {
+	c := &TypedCache[K, V]{
+		config: newConfig(opts...),
+		data:   make(map[K]*cacheValue[K, V]),
+		stats:  &statsCounters{},
+	}
+	c.batcher = newBatcher[K, V](c.config)
+
+	if c.earlyExpiration > 0 || c.ttlJitter > 0 {
+		c.rnd = newLockedRand()
+	}
+
+	// a constructor returning no policy, such as NoEviction, is dropped so that newPolicy tells whether
+	// the cache has an eviction policy
+	if c.newPolicy != nil && c.newPolicy() == nil {
+		c.newPolicy = nil
+	}
+
+	c.resetPolicy()
+
+	if c.expvar {
+		publishExpvar(c.name, c.Stats)
+	}
+
+	return c
+}
+
+// NewTypedSync creates a new [TypedCache] instance that is safe for concurrent use by multiple goroutines.
+// Every operation, including the expiration of entries, is guarded by a read/write lock.
+func NewTypedSync[K comparable, V any](opts ...OptFunc) *TypedCache[K, V] {
+	c := NewTyped[K, V](opts...)
+	c.mu = &sync.RWMutex{}
+
+	return c
+}
+
+// Set sets a key-value pair in the cache.
+// If an error occurs, it will be returned, otherwise nil will be returned.
+func (c *TypedCache[K, V]) Set(key K, value V) error {
+	return c.store(key, value, setOptions{ttl: -1, sliding: c.slidingTtl, maxLifetime: c.maxLifetime})
+}
+
+// SetWithTtl sets a key-value pair in the cache with a TTL (time-to-live) in duration.
+// If an error occurs, it will be returned, otherwise nil will be returned.
+func (c *TypedCache[K, V]) SetWithTtl(key K, value V, ttl time.Duration) error {
+	return c.store(key, value, setOptions{ttl: ttl, sliding: c.slidingTtl, maxLifetime: c.maxLifetime})
+}
+
+// SetWithCost sets a key-value pair in the cache with a cost and a TTL (time-to-live) in duration.
+// The cost counts against the maximum cost of the cache, a cost of 0 or less means the cost is
+// estimated by the sizer of the cache.
+// If an error occurs, it will be returned, otherwise nil will be returned.
+func (c *TypedCache[K, V]) SetWithCost(key K, value V, cost int64, ttl time.Duration) error {
+	return c.store(key, value, setOptions{cost: cost, ttl: ttl, sliding: c.slidingTtl, maxLifetime: c.maxLifetime})
+}
+
+// setOptions holds the settings of an entry being set in the cache.
+type setOptions struct {
+	// cost is the cost of the entry, 0 or less means it is estimated by the sizer of the cache.
+	cost int64
+	// ttl is the TTL of the entry, -1 means the global TTL of the cache.
+	ttl time.Duration
+	// expiryDate is the absolute expiry date of the entry if not zero, the TTL then being the time left
+	// until that date unless provided.
+	expiryDate time.Time
+	// sliding defines whether the TTL of the entry is sliding.
+	sliding bool
+	// maxLifetime is the maximum lifetime of the entry if its TTL is sliding, 0 or less means unlimited.
+	maxLifetime time.Duration
+	// deadline is the date past which the entry expires if its TTL is sliding, it overrides the maximum
+	// lifetime if not zero.
+	deadline time.Time
+	// negative defines whether the entry caches the absence of a value for the key.
+	negative bool
+	// delta is the time it took to load the entry, 0 if not loaded.
+	delta time.Duration
+}
+
+// store sets a key-value pair in the cache with the provided settings, like set, reporting the operation
+// to the observer of the cache.
+func (c *TypedCache[K, V]) store(key K, value V, opts setOptions) error {
+	obs := c.observeKey(context.Background(), OpSet, key)
+	err := c.set(key, value, opts)
+	obs.done(errOutcome(err))
+
+	return err
+}
+
+// set sets a key-value pair in the cache with the provided settings.
+// An expiry date that is not in the future expires the key instead, see expire.
+func (c *TypedCache[K, V]) set(key K, value V, opts setOptions) error {
+	c.lock()
+	defer c.unlock()
+
+	now := c.now()
+
+	if !opts.expiryDate.IsZero() && !opts.expiryDate.After(now) {
+		if val, ok := c.data[key]; ok {
+			c.expire(key, val, opts.expiryDate, now)
+		}
+
+		return nil
+	}
+
+	cost := opts.cost
+	if cost <= 0 {
+		cost = c.sizer(value)
+	}
+
+	if c.maxCost != -1 && cost > c.maxCost {
+		return ErrCostTooLarge
+	}
+
+	if !c.makeRoom(key, cost) {
+		return ErrCacheFull
+	}
+
+	old, ok := c.data[key]
+
+	if ok {
+		c.unschedule(old)
+		c.cost -= old.cost
+
+		if old.negative {
+			c.negatives--
+		}
+
+		if old.expired(now) {
+			c.removed(old, Expired)
+		} else {
+			c.removed(old, Replaced)
+		}
+	}
+
+	keyTtl := c.stdTtl
+	if !opts.expiryDate.IsZero() && opts.ttl > 0 {
+		// a restored entry keeps its TTL along with its expiry date
+		keyTtl = opts.ttl
+	} else if !opts.expiryDate.IsZero() {
+		keyTtl = opts.expiryDate.Sub(now)
+	} else if opts.ttl > -1 {
+		keyTtl = c.jitter(opts.ttl)
+	} else {
+		keyTtl = c.jitter(keyTtl)
+	}
+
+	val := &cacheValue[K, V]{
+		key:      key,
+		value:    value,
+		ttl:      keyTtl,
+		cost:     cost,
+		sliding:  opts.sliding && keyTtl > 0,
+		negative: opts.negative,
+		delta:    opts.delta,
+		index:    -1,
+	}
+
+	if val.sliding && !opts.deadline.IsZero() {
+		val.deadline = opts.deadline
+	} else if val.sliding && opts.maxLifetime > 0 {
+		val.deadline = now.Add(opts.maxLifetime)
+	}
+
+	val.expiryDate = val.expiryFrom(now)
+	if !opts.expiryDate.IsZero() {
+		val.expiryDate = opts.expiryDate
+	}
+
+	c.data[key] = val
+	c.cost += cost
+
+	if val.negative {
+		c.negatives++
+	} else {
+		c.stats.set()
+	}
+
+	switch {
+	case val.negative:
+		// caching the absence of a value is not a change visible to the subscribers
+	case old != nil && old.found(now):
+		c.published(TypedEvent[K, V]{Type: EventUpdate, Key: key, OldValue: old.value, NewValue: value})
+	default:
+		c.published(TypedEvent[K, V]{Type: EventSet, Key: key, NewValue: value})
+	}
+
+	if c.policy != nil {
+		if ok {
+			c.policy.OnUpdate(key)
+		} else {
+			c.policy.OnInsert(key)
+		}
+	}
+
+	c.reschedule(val)
+
+	return nil
+}
+
+// Get returns the value associated with the provided key from the cache.
+// It returns the value if found in the cache.
+// If an error occurs, it will be returned along with the zero value of V, otherwise nil will be returned.
+func (c *TypedCache[K, V]) Get(key K) (V, error) {
+	obs := c.observeKey(context.Background(), OpGet, key)
+	value, err := c.read(key)
+	obs.done(foundOutcome(err == nil))
+
+	return value, err
+}
+
+// read returns the value associated with the provided key from the cache, like get, recording the read
+// in the statistics of the cache.
+func (c *TypedCache[K, V]) read(key K) (V, error) {
+	value, err := c.get(key)
+	c.stats.read(err == nil)
+
+	return value, err
+}
+
+// get returns the value associated with the provided key from the cache, like Get, without recording
+// the read in the statistics of the cache.
+func (c *TypedCache[K, V]) get(key K) (V, error) {
+	write := c.lockEntry(key)
+	defer c.unlockEntry(write)
+
+	var zero V
+
+	val, ok := c.data[key]
+
+	if !ok {
+		return zero, ErrKeyNotFound
+	}
+
+	now := c.now()
+
+	if val.expired(now) {
+		return zero, ErrKeyNotFound
+	}
+
+	if val.negative {
+		return zero, ErrNegativeCached
+	}
+
+	if c.expiresEarly(val, now) {
+		return zero, ErrKeyNotFound
+	}
+
+	c.slide(val, now)
+
+	if c.policy != nil {
+		c.policy.OnAccess(key)
+	}
+
+	return val.value, nil
+}
+
+// GetAndDelete returns the value associated with the provided key from the cache and removes it.
+// It returns the value if found in the cache.
+// If an error occurs, it will be returned along with the zero value of V, otherwise nil will be returned.
+func (c *TypedCache[K, V]) GetAndDelete(key K) (V, error) {
+	obs := c.observeKey(context.Background(), OpGetAndDelete, key)
+	value, err := c.getAndDelete(key)
+	obs.done(foundOutcome(err == nil))
+
+	return value, err
+}
+
+// getAndDelete is GetAndDelete, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) getAndDelete(key K) (V, error) {
+	c.lock()
+	defer c.unlock()
+
+	var zero V
+
+	val, ok := c.data[key]
+
+	if !ok || val.expired(c.now()) {
+		c.stats.read(false)
+
+		return zero, ErrKeyNotFound
+	}
+
+	if val.negative {
+		c.stats.read(false)
+
+		return zero, ErrNegativeCached
+	}
+
+	c.stats.read(true)
+	c.remove(key, val, Deleted)
+
+	return val.value, nil
+}
+
+// Delete removes the entry associated with the provided key from the cache if it exists.
+// It returns the number of deleted items from the cache.
+func (c *TypedCache[K, V]) Delete(key K) int {
+	obs := c.observeKey(context.Background(), OpDelete, key)
+	count := c.deleteKey(key)
+	obs.done(foundOutcome(count > 0))
+
+	return count
+}
+
+// deleteKey is Delete, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) deleteKey(key K) int {
+	c.lock()
+	defer c.unlock()
+
+	count := 0
+
+	val, ok := c.data[key]
+
+	if !ok {
+		return 0
+	}
+
+	c.remove(key, val, Deleted)
+	count++
+
+	return count
+}
+
+// ChangeTtl changes the TTL associated with the provided key in the cache.
+// It returns a bool indicating whether a change in TTL has occurred or not.
+func (c *TypedCache[K, V]) ChangeTtl(key K, ttl time.Duration) bool {
+	obs := c.observeKey(context.Background(), OpChangeTtl, key)
+	ok := c.changeTtl(key, ttl)
+	obs.done(foundOutcome(ok))
+
+	return ok
+}
+
+// changeTtl is ChangeTtl, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) changeTtl(key K, ttl time.Duration) bool {
+	c.lock()
+	defer c.unlock()
+
+	val, ok := c.data[key]
+
+	if !ok || !val.found(c.now()) {
+		return false
+	}
+
+	if ttl < 0 {
+		c.remove(key, val, Deleted)
+
+		return true
+	}
+
+	val.ttl = ttl
+	val.sliding = val.sliding && ttl > 0
+	val.expiryDate = val.expiryFrom(c.now())
+
+	c.reschedule(val)
+
+	return true
+}
+
+// GetTtl returns the TTL, as a duration, of the provided key in the cache.
+// It returns -1 if the key does not exist.
+// The TTL is the duration the entry was set to live for, the time left before it expires is
+// returned by TimeToLive.
+func (c *TypedCache[K, V]) GetTtl(key K) time.Duration {
+	obs := c.observeKey(context.Background(), OpGetTtl, key)
+	ttl := c.getTtl(key)
+	obs.done(foundOutcome(ttl != -1))
+
+	return ttl
+}
+
+// getTtl is GetTtl, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) getTtl(key K) time.Duration {
+	c.rLock()
+	defer c.rUnlock()
+
+	val, ok := c.data[key]
+
+	if !ok || !val.found(c.now()) {
+		return -1
+	}
+
+	return val.ttl
+}
+
+// Keys returns the list of keys, as a slice, in the cache.
+// The keys cached as not found are not included, see [WithNegativeTtl], nor are the expired entries
+// deleted on expiry that are only kept to be returned stale, see [WithStaleWhileRevalidate].
+func (c *TypedCache[K, V]) Keys() []K {
+	obs := c.observe(context.Background(), OpKeys, "")
+	keys := c.keys()
+	obs.done(OutcomeSuccess)
+
+	return keys
+}
+
+// keys is Keys, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) keys() []K {
+	c.rLock()
+	defer c.rUnlock()
+
+	keys := make([]K, 0, len(c.data))
+	now := c.now()
+
+	for k, v := range c.data {
+		if !v.negative && !(c.deleteOnExpire && v.expired(now)) {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// Has returns a bool whether the key exists in the cache or not.
+func (c *TypedCache[K, V]) Has(key K) bool {
+	obs := c.observeKey(context.Background(), OpHas, key)
+	ok := c.has(key)
+	obs.done(foundOutcome(ok))
+
+	return ok
+}
+
+// has is Has, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) has(key K) bool {
+	write := c.lockEntry(key)
+	defer c.unlockEntry(write)
+
+	val, ok := c.data[key]
+
+	if !ok {
+		return false
+	}
+
+	now := c.now()
+
+	if !val.found(now) {
+		return false
+	}
+
+	c.slide(val, now)
+
+	return true
+}
+
+// Clear clears the cache by emptying the store.
+func (c *TypedCache[K, V]) Clear() {
+	obs := c.observe(context.Background(), OpClear, "")
+	c.clearAll()
+	obs.done(OutcomeSuccess)
+}
+
+// clearAll is Clear, without reporting the operation to the observer of the cache.
+func (c *TypedCache[K, V]) clearAll() {
+	c.lock()
+	defer c.unlock()
+
+	if len(c.data) == 0 {
+		return
+	}
+
+	for _, val := range c.data {
+		c.removed(val, Cleared)
+	}
+
+	c.release(len(c.data))
+	c.releaseCost(c.cost)
+	c.data = make(map[K]*cacheValue[K, V])
+	c.cost = 0
+	c.negatives = 0
+	c.expiries = nil
+
+	c.resetPolicy()
+}
+
+// remove deletes the provided entry from the cache for the provided reason and notifies the eviction policy.
+func (c *TypedCache[K, V]) remove(key K, val *cacheValue[K, V], reason RemovalReason) {
+	c.unlink(key, val, reason)
+
+	if c.policy != nil {
+		c.policy.OnRemove(key)
+	}
+}
+
+// unlink deletes the provided entry from the data store for the provided reason, unschedules its expiry
+// and releases its key slot and cost.
+func (c *TypedCache[K, V]) unlink(key K, val *cacheValue[K, V], reason RemovalReason) {
+	c.unschedule(val)
+
+	delete(c.data, key)
+	c.release(1)
+	c.releaseCost(val.cost)
+	c.cost -= val.cost
+
+	if val.negative {
+		c.negatives--
+	}
+	c.removed(val, reason)
+}
+
+// resetPolicy creates a new instance of the eviction policy, if any, sized for the cache's capacity.
+func (c *TypedCache[K, V]) resetPolicy() {
+	if c.newPolicy == nil {
+		return
+	}
+
+	c.policy = c.newPolicy()
+
+	if p, ok := c.policy.(interface{ setCapacity(int) }); ok {
+		p.setCapacity(c.maxKeys)
+	}
+}
+
+// makeRoom evicts entries selected by the eviction policy until the key, with the provided cost,
+// fits in the cache without exceeding its maximum number of keys or its maximum cost. The expired entries
+// only kept to be returned stale are dropped first, and when there is nothing left to evict, the keys
+// cached as not found are dropped, so that neither keeps a value out.
+// The key slot is reserved if the key is new, along with the cost it adds.
+// It returns false if the key does not fit and there is no eviction policy or no more entries to evict.
+func (c *TypedCache[K, V]) makeRoom(key K, cost int64) bool {
+	for {
+		val, ok := c.data[key]
+
+		delta := cost
+		if ok {
+			delta -= val.cost
+		}
+
+		if c.reserveCost(delta) {
+			if ok || c.reserve() {
+				return true
+			}
+
+			c.releaseCost(delta)
+		} else if c.sharedCost != nil && atomic.LoadInt64(c.sharedCost)-c.cost+cost > c.maxCost {
+			// the entries of the other shards leave no room for the key, even once this shard is emptied
+			return false
+		}
+
+		if !c.dropExpired() && !c.evict(key) && !c.dropNegative(key) {
+			return false
+		}
+	}
+}
+
+// dropExpired removes the entry deleted on expiry that expired the earliest, if it has expired and is
+// only kept to be returned stale, see [WithStaleWhileRevalidate].
+// It returns false if there is no such entry.
+func (c *TypedCache[K, V]) dropExpired() bool {
+	if len(c.expiries) == 0 {
+		return false
+	}
+
+	val := c.expiries[0]
+	if !val.expired(c.now()) {
+		return false
+	}
+
+	c.remove(val.key, val, Expired)
+
+	return true
+}
+
+// dropNegative removes a key cached as not found other than the provided key.
+// It returns false if there is no such key.
+func (c *TypedCache[K, V]) dropNegative(key K) bool {
+	if c.negatives == 0 {
+		return false
+	}
+
+	for k, val := range c.data {
+		if val.negative && k != key {
+			c.remove(k, val, Evicted)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// evict removes the entry selected by the eviction policy, other than the provided key which is making
+// room for itself.
+// It returns false if there is no eviction policy or if it has no more entries to evict.
+func (c *TypedCache[K, V]) evict(key K) bool {
+	if c.policy == nil {
+		return false
+	}
+
+	victim, ok := c.policy.Victim()
+
+	if ok && victim.(K) == key {
+		victim, ok = c.policy.Victim()
+		c.policy.OnInsert(key)
+	}
+
+	if !ok {
+		return false
+	}
+
+	if val, ok := c.data[victim.(K)]; ok {
+		c.unlink(victim.(K), val, Evicted)
+	}
+
+	return true
+}
+
+// reserve reports whether a new key can be added to the cache without exceeding the maximum number of keys.
+// When the key count is shared between shards, a slot is reserved in the shared count.
+// The keys of a shard with an eviction policy are limited by the shard alone, since its policy can only
+// evict its own keys to make room.
+func (c *TypedCache[K, V]) reserve() bool {
+	if c.keyCount == nil || c.newPolicy != nil {
+		if c.maxKeys != -1 && len(c.data) >= c.maxKeys {
+			return false
+		}
+
+		if c.keyCount != nil {
+			atomic.AddInt64(c.keyCount, 1)
+		}
+
+		return true
+	}
+
+	for {
+		n := atomic.LoadInt64(c.keyCount)
+
+		if c.maxKeys != -1 && n >= int64(c.maxKeys) {
+			return false
+		}
+
+		if atomic.CompareAndSwapInt64(c.keyCount, n, n+1) {
+			return true
+		}
+	}
+}
+
+// release gives back n slots reserved in the shared key count, if any.
+func (c *TypedCache[K, V]) release(n int) {
+	if c.keyCount != nil && n > 0 {
+		atomic.AddInt64(c.keyCount, -int64(n))
+	}
+}
+
+// reserveCost reports whether the provided cost can be added to the cache without exceeding its maximum cost.
+// When the cost is shared between shards, it is reserved in the shared cost.
+func (c *TypedCache[K, V]) reserveCost(cost int64) bool {
+	if c.sharedCost == nil {
+		return c.maxCost == -1 || c.cost+cost <= c.maxCost
+	}
+
+	for {
+		n := atomic.LoadInt64(c.sharedCost)
+
+		if c.maxCost != -1 && cost > 0 && n+cost > c.maxCost {
+			return false
+		}
+
+		if atomic.CompareAndSwapInt64(c.sharedCost, n, n+cost) {
+			return true
+		}
+	}
+}
+
+// releaseCost gives back the provided cost reserved in the shared cost, if any.
+func (c *TypedCache[K, V]) releaseCost(cost int64) {
+	if c.sharedCost != nil && cost != 0 {
+		atomic.AddInt64(c.sharedCost, -cost)
+	}
+}
+
+// lock acquires the write lock of the cache if it is used concurrently, otherwise it runs the timer
+// callbacks that are due.
+func (c *TypedCache[K, V]) lock() {
+	if c.mu != nil {
+		c.mu.Lock()
+	} else {
+		c.runDue()
+	}
+}
+
+// unlock releases the write lock of the cache if it is used concurrently, then notifies the removals and
+// sends the events that occurred while it was held, so that the callback and the subscribers can safely
+// call back into the cache.
+func (c *TypedCache[K, V]) unlock() {
+	removals, events, subscribers := c.removals, c.events, c.subscribers
+	c.removals, c.events = nil, nil
+
+	if c.mu != nil {
+		c.mu.Unlock()
+	}
+
+	c.notifyRemovals(removals)
+	publish(subscribers, events)
+}
+
+// lockEntry acquires the lock needed to read the entry associated with the provided key, which is the
+// write lock if reading it modifies the cache, that is when the eviction policy is notified of the access
+// or when the entry has a sliding TTL, otherwise the read lock.
+// It returns whether the write lock was acquired, to be passed to unlockEntry.
+// Whether there is an eviction policy is decided from newPolicy, which unlike policy is not replaced by Clear.
+func (c *TypedCache[K, V]) lockEntry(key K) bool {
+	if c.newPolicy != nil {
+		c.lock()
+
+		return true
+	}
+
+	c.rLock()
+
+	if val, ok := c.data[key]; !ok || !val.sliding {
+		return false
+	}
+
+	// the read lock cannot be upgraded, the entry is looked up again once the write lock is acquired
+	c.rUnlock()
+	c.lock()
+
+	return true
+}
+
+// unlockEntry releases the lock acquired by lockEntry.
+func (c *TypedCache[K, V]) unlockEntry(write bool) {
+	if write {
+		c.unlock()
+	} else {
+		c.rUnlock()
+	}
+}
+
+// rLock acquires the read lock of the cache if it is used concurrently, otherwise it runs the timer
+// callbacks that are due.
+func (c *TypedCache[K, V]) rLock() {
+	if c.mu != nil {
+		c.mu.RLock()
+	} else {
+		c.runDue()
+	}
+}
+
+// onTimer returns the function to call when a timer of the cache fires, which calls the provided callback
+// if the cache is used concurrently. A non-concurrent cache is not locked, so the callback, which modifies
+// the cache, cannot run on the goroutine of the timer: the provided flag is set instead, for the next
+// operation on the cache to call the callback, see runDue.
+func (c *TypedCache[K, V]) onTimer(due *int32, callback func()) func() {
+	return func() {
+		if c.mu != nil {
+			callback()
+		} else {
+			atomic.StoreInt32(due, 1)
+		}
+	}
+}
+
+// runDue calls the callbacks of the timers of a non-concurrent cache that fired since the last operation.
+func (c *TypedCache[K, V]) runDue() {
+	if atomic.LoadInt32(&c.expiryDue) != 0 && atomic.SwapInt32(&c.expiryDue, 0) != 0 {
+		c.expireDue()
+	}
+
+	if atomic.LoadInt32(&c.cleanupDue) != 0 && atomic.SwapInt32(&c.cleanupDue, 0) != 0 {
+		c.cleanup()
+	}
+}
+
+// rUnlock releases the read lock of the cache if it is used concurrently.
+func (c *TypedCache[K, V]) rUnlock() {
+	if c.mu != nil {
+		c.mu.RUnlock()
+	}
+}
@@ -0,0 +1,131 @@
+package gocache
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type typedTestValue struct {
+	name  string
+	count int
+}
+
+func TestTypedCacheSetAndGet(t *testing.T) {
+	// Setup
+	c := NewTyped[int, typedTestValue]()
+	c.Set(1, typedTestValue{"one", 1})
+	c.SetWithTtl(2, typedTestValue{"two", 2}, 100*time.Millisecond)
+
+	// Test Case 1: Key found
+	t.Run("key found", func(t *testing.T) {
+		value, err := c.Get(1)
+
+		if err != nil {
+			t.Errorf("err - got: %v, want nil", err)
+		}
+
+		if value.name != "one" || value.count != 1 {
+			t.Errorf("value - got: %v, want: {one 1}", value)
+		}
+	})
+
+	// Test Case 2: Key not found
+	t.Run("key not found", func(t *testing.T) {
+		value, err := c.Get(3)
+
+		if value != (typedTestValue{}) {
+			t.Errorf("value - got: %v, want: zero value", value)
+		}
+
+		if !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("err - got: %v, want: ErrKeyNotFound", err)
+		}
+	})
+
+	// Test Case 3: Key found before and after TTL
+	t.Run("key found (before and after TTL)", func(t *testing.T) {
+		if !c.Has(2) {
+			t.Errorf("has key 2 - got: false, want: true")
+		}
+
+		time.Sleep(150 * time.Millisecond)
+
+		if _, err := c.Get(2); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("err - got: %v, want: ErrKeyNotFound", err)
+		}
+	})
+
+	// Test Case 4: Get and delete
+	t.Run("get and delete", func(t *testing.T) {
+		value, err := c.GetAndDelete(1)
+
+		if err != nil || value.name != "one" {
+			t.Errorf("GetAndDelete 1 - got: %v, %v, want: {one 1}, nil", value, err)
+		}
+
+		if keys := c.Keys(); len(keys) != 0 {
+			t.Errorf("keys length - got: %d, want: 0", len(keys))
+		}
+	})
+}
+
+func TestTypedCacheOpts(t *testing.T) {
+	c := NewTyped[string, []byte](
+		WithStdTtl(time.Second),
+		WithDeleteOnExpire(false),
+		WithMaxKeys(1),
+	)
+
+	if c.stdTtl != time.Second {
+		t.Errorf("stdTtl - got: %v, want: 1s", c.stdTtl)
+	}
+
+	if c.deleteOnExpire {
+		t.Errorf("deleteOnExpire - got: true, want: false")
+	}
+
+	if err := c.Set("k1", []byte("value1")); err != nil {
+		t.Errorf("Set k1: err - got: %v, want: nil", err)
+	}
+
+	if err := c.Set("k2", []byte("value2")); !errors.Is(err, ErrCacheFull) {
+		t.Errorf("Set k2: err - got: %v, want: ErrCacheFull", err)
+	}
+
+	if ttl := c.GetTtl("k1"); ttl != time.Second {
+		t.Errorf("GetTtl k1 - got: %v, want: 1s", ttl)
+	}
+}
+
+func TestTypedCacheSyncConcurrentAccess(t *testing.T) {
+	c := NewTypedSync[int, int]()
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < stressGoroutines; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < stressIterations; i++ {
+				key := (g*stressIterations + i) % keyPoolSize
+
+				switch i % 4 {
+				case 0:
+					c.SetWithTtl(key, i, time.Millisecond)
+				case 1:
+					c.Get(key)
+				case 2:
+					c.Delete(key)
+				case 3:
+					c.Keys()
+				}
+			}
+		}(g)
+	}
+
+	wg.Wait()
+}
@@ -4,18 +4,48 @@ import "time"
 
 // cacheValue is a structure that represents the cache value.
 // It contains the actual value, the TTL and the expiry date of the value.
-type cacheValue struct {
+type cacheValue[K comparable, V any] struct {
+	// key is the key of the cache entry.
+	key K
 	// value is the actual value of the cache entry.
-	value any
+	value V
 	// ttl is the time-to-live duration of the cache value entry.
 	ttl time.Duration
 	// expiryDate is the cache entry value expiration date.
 	expiryDate time.Time
-	// timer is timer of the cache value if delete on expire is set on it.
-	timer *time.Timer
+	// cost is the cost of the cache entry, counted against the maximum cost of the cache.
+	cost int64
+	// sliding defines whether the expiry date is pushed forward by the TTL every time the entry is read.
+	sliding bool
+	// deadline is the date past which a sliding entry cannot be extended, it is zero if unlimited.
+	deadline time.Time
+	// delta is the time it took to load the entry, used for its early expiration, 0 if not loaded.
+	delta time.Duration
+	// negative defines whether the entry caches the absence of a value for its key, see [WithNegativeTtl].
+	negative bool
+	// index is the position of the cache entry in the expiry heap, -1 if its expiry is not scheduled.
+	index int
 }
 
-// expired returns a flag whether the cache entry has expired or not.
-func (v *cacheValue) expired() bool {
-	return v.ttl > 0 && v.expiryDate.Before(time.Now().UTC())
+// expired returns a flag whether the cache entry has expired or not at the provided time.
+func (v *cacheValue[K, V]) expired(now time.Time) bool {
+	return v.ttl > 0 && v.expiryDate.Before(now)
+}
+
+// found returns a flag whether the cache entry holds a value at the provided time, that is it has not
+// expired and is not a negative entry.
+func (v *cacheValue[K, V]) found(now time.Time) bool {
+	return !v.negative && !v.expired(now)
+}
+
+// expiryFrom returns the expiry date of the cache entry from the provided time, which is capped by its
+// deadline if any.
+func (v *cacheValue[K, V]) expiryFrom(now time.Time) time.Time {
+	expiryDate := now.Add(v.ttl)
+
+	if !v.deadline.IsZero() && expiryDate.After(v.deadline) {
+		return v.deadline
+	}
+
+	return expiryDate
 }
@@ -0,0 +1,52 @@
+package gocache
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// lockedRand is a source of random numbers that is safe for concurrent use.
+// It is seeded from the current time, so that caches in different processes draw different numbers.
+type lockedRand struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// newLockedRand creates a new lockedRand seeded from the current time.
+func newLockedRand() *lockedRand {
+	return &lockedRand{rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Float64 returns a random number in the range [0, 1).
+func (r *lockedRand) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.rnd.Float64()
+}
+
+// expiresEarly returns whether the loaded entry is considered expired ahead of its expiry date at the
+// provided time, following the XFetch algorithm: the closer the entry is to its expiry date, and the
+// longer it took to load, the more likely it is to expire early. This way, an entry shared by many
+// callers is likely to be reloaded by one of them before it expires for all of them.
+func (c *TypedCache[K, V]) expiresEarly(val *cacheValue[K, V], now time.Time) bool {
+	if c.earlyExpiration <= 0 || val.delta <= 0 || val.ttl <= 0 {
+		return false
+	}
+
+	// -log(u) for u in (0, 1] is exponentially distributed, so that early expirations are rare far from the expiry date
+	gap := -float64(val.delta) * c.earlyExpiration * math.Log(1-c.rnd.Float64())
+
+	return !now.Add(time.Duration(gap)).Before(val.expiryDate)
+}
+
+// jitter returns the provided TTL randomly spread by up to the TTL jitter of the cache in either direction.
+func (c *TypedCache[K, V]) jitter(ttl time.Duration) time.Duration {
+	if c.ttlJitter <= 0 || ttl <= 0 {
+		return ttl
+	}
+
+	return ttl + time.Duration((2*c.rnd.Float64()-1)*c.ttlJitter*float64(ttl))
+}
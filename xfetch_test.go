@@ -0,0 +1,125 @@
+package gocache
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestCacheEarlyExpiration(t *testing.T) {
+	// Setup
+	clk := &stubClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewSync(WithClock(clk), WithEarlyExpiration(1))
+	c.rnd = &lockedRand{rnd: rand.New(rand.NewSource(1))}
+	ctx := context.Background()
+
+	var version int
+
+	loader := func(ctx context.Context) (any, time.Duration, error) {
+		// the load takes a second
+		clk.advance(time.Second)
+		version++
+
+		return version, 100 * time.Second, nil
+	}
+
+	c.GetOrLoad(ctx, "loaded", loader)
+	c.SetWithTtl("set", "value", 100*time.Second)
+
+	// earlyMisses returns the number of early misses of the key out of the provided number of reads.
+	earlyMisses := func(key string, reads int) int {
+		misses := 0
+
+		for i := 0; i < reads; i++ {
+			if _, err := c.Get(key); errors.Is(err, ErrKeyNotFound) {
+				misses++
+			}
+		}
+
+		return misses
+	}
+
+	// Test Case 1: Entries far from their expiry date do not expire early
+	t.Run("far from expiry", func(t *testing.T) {
+		clk.advance(50 * time.Second)
+
+		if misses := earlyMisses("loaded", 1000); misses != 0 {
+			t.Errorf("early misses - got: %d, want: 0", misses)
+		}
+	})
+
+	// Test Case 2: Entries close to their expiry date are likely to expire early
+	t.Run("close to expiry", func(t *testing.T) {
+		clk.advance(49 * time.Second)
+
+		// the probability of an early miss a second before expiry is e^-1, around 37%
+		if misses := earlyMisses("loaded", 1000); misses < 300 || misses > 440 {
+			t.Errorf("early misses - got: %d, want: around 370", misses)
+		}
+	})
+
+	// Test Case 3: Entries that were not loaded do not expire early
+	t.Run("not loaded", func(t *testing.T) {
+		if misses := earlyMisses("set", 1000); misses != 0 {
+			t.Errorf("early misses - got: %d, want: 0", misses)
+		}
+	})
+
+	// Test Case 4: GetOrLoad reloads the entries expiring early in the background
+	t.Run("reload", func(t *testing.T) {
+		clk.advance(time.Second)
+
+		if value, err := c.GetOrLoad(ctx, "loaded", loader); value != 1 || err != nil {
+			t.Errorf("GetOrLoad loaded - got: %v, %v, want: 1, nil", value, err)
+		}
+
+		waitForLoads(t, c.Cache)
+
+		if value, _ := c.Get("loaded"); value != 2 {
+			t.Errorf("Get loaded - got: %v, want: 2", value)
+		}
+	})
+}
+
+func TestCacheTtlJitter(t *testing.T) {
+	// Setup
+	c := New(WithStdTtl(10*time.Minute), WithTtlJitter(10))
+
+	// Test Case 1: TTLs are spread within the jitter
+	t.Run("spread", func(t *testing.T) {
+		ttls := make(map[time.Duration]bool)
+
+		for i := 0; i < 100; i++ {
+			c.Set("k1", "value1")
+			ttl := c.GetTtl("k1")
+
+			if ttl < 9*time.Minute || ttl > 11*time.Minute {
+				t.Errorf("GetTtl k1 - got: %v, want: between 9m and 11m", ttl)
+			}
+
+			ttls[ttl] = true
+		}
+
+		if len(ttls) < 50 {
+			t.Errorf("distinct TTLs - got: %d, want: at least 50", len(ttls))
+		}
+	})
+
+	// Test Case 2: Unlimited TTLs and expiry dates are not spread
+	t.Run("not spread", func(t *testing.T) {
+		c.SetWithTtl("k2", "value2", 0)
+
+		if ttl := c.GetTtl("k2"); ttl != 0 {
+			t.Errorf("GetTtl k2 - got: %v, want: 0", ttl)
+		}
+
+		expiryDate := time.Now().Add(time.Hour)
+		c.SetWithExpiry("k3", "value3", expiryDate)
+
+		if !c.data["k3"].expiryDate.Equal(expiryDate.UTC()) {
+			t.Errorf("expiryDate k3 - got: %v, want: %v", c.data["k3"].expiryDate, expiryDate.UTC())
+		}
+	})
+}